@@ -0,0 +1,588 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/linkwardenmcp"
+)
+
+// configFileName is the name of the config file in the home directory
+const configFileName = "linkwarden-mcp-server.yaml"
+
+// durationPattern matches the durations accepted by time.ParseDuration
+const durationPattern = `^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+
+// configKey describes a key of the config file. config init writes it
+// with its description, and config schema turns it into a property.
+type configKey struct {
+	name        string
+	description string
+	schema      map[string]any
+
+	// example is written commented out when the key has no value
+	example string
+}
+
+// stringList is the schema of a list of strings
+func stringList(items map[string]any) map[string]any {
+	return map[string]any{"type": "array", "items": items}
+}
+
+// toolsetNames returns the toolsets that can be enabled in the config
+// file. instances is only there when several instances are configured.
+func toolsetNames() []any {
+	var names []any
+	for _, name := range linkwardenmcp.ToolsetNames(true) {
+		names = append(names, name)
+	}
+	return names
+}
+
+// limitSchema is the schema of the budget of reads or writes
+var limitSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"rate":           map[string]any{"type": "number", "minimum": 0},
+		"burst":          map[string]any{"type": "integer", "minimum": 0},
+		"max_concurrent": map[string]any{"type": "integer", "minimum": 0},
+	},
+	"additionalProperties": false,
+}
+
+// limitsSchema is the schema of the request limits of an instance
+var limitsSchema = map[string]any{
+	"type":                 "object",
+	"properties":           map[string]any{"read": limitSchema, "write": limitSchema},
+	"additionalProperties": false,
+}
+
+// accessSchema is the schema of an access level of the access policy
+var accessSchema = map[string]any{"type": "string", "enum": []any{"none", "read", "write"}}
+
+// configKeys lists every key of the config file
+var configKeys = []configKey{
+	{
+		name:        "base_url",
+		description: "URL of your Linkwarden instance",
+		schema:      map[string]any{"type": "string", "format": "uri"},
+		example:     "base_url: https://linkwarden.example.com",
+	},
+	{
+		name:        "token",
+		description: "Linkwarden API token. Prefer token_file or token_command, which keep it out of this file",
+		schema:      map[string]any{"type": "string"},
+		example:     "token: your-api-token",
+	},
+	{
+		name:        "token_file",
+		description: "File holding the Linkwarden API token, such as a Docker or Kubernetes secret",
+		schema:      map[string]any{"type": "string"},
+		example:     "token_file: /run/secrets/linkwarden_token",
+	},
+	{
+		name:        "token_command",
+		description: "Shell command printing the Linkwarden API token, such as a password manager",
+		schema:      map[string]any{"type": "string"},
+		example:     "token_command: op read op://Private/Linkwarden/token",
+	},
+	{
+		name:        "instances",
+		description: "Named Linkwarden instances, used instead of base_url and the token keys",
+		schema: map[string]any{
+			"type": "object",
+			"additionalProperties": map[string]any{
+				"type":     "object",
+				"required": []any{"base_url"},
+				"properties": map[string]any{
+					"base_url":      map[string]any{"type": "string", "format": "uri"},
+					"token":         map[string]any{"type": "string"},
+					"token_file":    map[string]any{"type": "string"},
+					"token_command": map[string]any{"type": "string"},
+					"limits":        limitsSchema,
+				},
+				"additionalProperties": false,
+			},
+		},
+		example: "instances:\n" +
+			"  work:\n" +
+			"    base_url: https://links.example.com\n" +
+			"    token_file: /run/secrets/work_token\n" +
+			"  personal:\n" +
+			"    base_url: https://linkwarden.home.example\n" +
+			"    token_command: pass show linkwarden",
+	},
+	{
+		name:        "default_instance",
+		description: "Instance used by tool calls that do not name one",
+		schema:      map[string]any{"type": "string"},
+		example:     "default_instance: work",
+	},
+	{
+		name:        "log_file",
+		description: "Path to the log file",
+		schema:      map[string]any{"type": "string"},
+		example:     "log_file: /var/log/linkwarden-mcp-server.log",
+	},
+	{
+		name:        "log_level",
+		description: "Minimum level written to the log file",
+		schema:      map[string]any{"type": "string", "enum": []any{"debug", "info", "warn", "error"}},
+		example:     "log_level: info",
+	},
+	{
+		name:        "toolsets",
+		description: "Toolsets to enable, all of them when empty. instances exists only with several instances",
+		schema:      stringList(map[string]any{"type": "string", "enum": toolsetNames()}),
+		example:     "toolsets: [search, collection, link, tags]",
+	},
+	{
+		name:        "tools",
+		description: "Tools to enable within the toolsets, globs like get_* allowed",
+		schema:      stringList(map[string]any{"type": "string"}),
+		example:     `tools: ["get_*", create_link]`,
+	},
+	{
+		name:        "exclude_tools",
+		description: "Tools to disable, globs like delete_* allowed",
+		schema:      stringList(map[string]any{"type": "string"}),
+		example:     `exclude_tools: ["delete_*"]`,
+	},
+	{
+		name:        "tool_prefix",
+		description: "Prefix for every tool name",
+		schema:      map[string]any{"type": "string"},
+		example:     "tool_prefix: lw_",
+	},
+	{
+		name:        "tool_overrides",
+		description: "Descriptions replacing those of tools and their parameters, keyed by tool name",
+		schema: map[string]any{
+			"type": "object",
+			"additionalProperties": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"description": map[string]any{"type": "string"},
+					"parameters": map[string]any{
+						"type":                 "object",
+						"additionalProperties": map[string]any{"type": "string"},
+					},
+				},
+				"additionalProperties": false,
+			},
+		},
+		example: "tool_overrides:\n" +
+			"  search_links:\n" +
+			"    description: Search my bookmarks before searching the web.\n" +
+			"    parameters:\n" +
+			"      searchQueryString: Words to look for.",
+	},
+	{
+		name:        "read_only",
+		description: "Disable every tool that changes Linkwarden data",
+		schema:      map[string]any{"type": "boolean"},
+		example:     "read_only: false",
+	},
+	{
+		name:        "dynamic_toolsets",
+		description: "Expose only toolset discovery tools and enable toolsets on demand",
+		schema:      map[string]any{"type": "boolean"},
+		example:     "dynamic_toolsets: false",
+	},
+	{
+		name:        "tool_timeout",
+		description: "Default timeout for a tool call, 0 to disable",
+		schema:      map[string]any{"type": "string", "pattern": durationPattern},
+		example:     "tool_timeout: 60s",
+	},
+	{
+		name:        "tool_timeouts",
+		description: "Timeouts of single tools, keyed by tool name",
+		schema: map[string]any{
+			"type":                 "object",
+			"additionalProperties": map[string]any{"type": "string", "pattern": durationPattern},
+		},
+		example: "tool_timeouts:\n  search_links: 2m",
+	},
+	{
+		name:        "confirm_destructive",
+		description: "Ask the user to confirm destructive tool calls",
+		schema:      map[string]any{"type": "boolean"},
+		example:     "confirm_destructive: false",
+	},
+	{
+		name:        "max_retries",
+		description: "Retries of a Linkwarden request that failed temporarily, 0 to disable",
+		schema:      map[string]any{"type": "integer", "minimum": 0},
+		example:     "max_retries: 2",
+	},
+	{
+		name:        "retry_max_delay",
+		description: "Longest wait before retrying a Linkwarden request",
+		schema:      map[string]any{"type": "string", "pattern": durationPattern},
+		example:     "retry_max_delay: 10s",
+	},
+	{
+		name:        "cache_ttl",
+		description: "How long collections and tags are cached, 0 to disable",
+		schema:      map[string]any{"type": "string", "pattern": durationPattern},
+		example:     "cache_ttl: 1m",
+	},
+	{
+		name:        "breaker_threshold",
+		description: "Failed Linkwarden requests in a row after which tool calls fail fast, 0 to disable",
+		schema:      map[string]any{"type": "integer", "minimum": 0},
+		example:     "breaker_threshold: 5",
+	},
+	{
+		name:        "breaker_cooldown",
+		description: "How long tool calls fail fast before Linkwarden is tried again",
+		schema:      map[string]any{"type": "string", "pattern": durationPattern},
+		example:     "breaker_cooldown: 30s",
+	},
+	{
+		name:        "limits",
+		description: "Requests per second, burst and requests in flight allowed for reads and writes, unlimited when unset",
+		schema:      limitsSchema,
+		example: "limits:\n" +
+			"  read:\n" +
+			"    rate: 10\n" +
+			"    burst: 20\n" +
+			"    max_concurrent: 4\n" +
+			"  write:\n" +
+			"    rate: 2\n" +
+			"    max_concurrent: 1",
+	},
+	{
+		name:        "access_policy",
+		description: "Collections the tools may read and modify",
+		schema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"default": accessSchema,
+				"collections": map[string]any{
+					"type": "array",
+					"items": map[string]any{
+						"type": "object",
+						"properties": map[string]any{
+							"id":     map[string]any{"type": "integer"},
+							"name":   map[string]any{"type": "string"},
+							"access": accessSchema,
+						},
+						"required":             []any{"access"},
+						"additionalProperties": false,
+					},
+				},
+			},
+			"additionalProperties": false,
+		},
+		example: "access_policy:\n" +
+			"  default: read\n" +
+			"  collections:\n" +
+			"    - name: Inbox\n" +
+			"      access: write",
+	},
+}
+
+// configSchema returns a JSON Schema of the config file
+func configSchema() map[string]any {
+	properties := make(map[string]any, len(configKeys))
+	for _, key := range configKeys {
+		property := map[string]any{"description": key.description}
+		for k, v := range key.schema {
+			property[k] = v
+		}
+		properties[key.name] = property
+	}
+
+	return map[string]any{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"title":                "Linkwarden MCP Server configuration",
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
+
+// renderConfig writes the config file with every key and its
+// description. Keys with a value in values, given as YAML, are set and
+// the others are left as commented out examples.
+func renderConfig(values map[string]string) string {
+	var b strings.Builder
+	b.WriteString("# Linkwarden MCP Server configuration\n")
+	b.WriteString("# Run `linkwarden-mcp-server config schema` for a JSON Schema of this file.\n")
+
+	for _, key := range configKeys {
+		b.WriteString("\n# " + key.description + "\n")
+		if value, ok := values[key.name]; ok {
+			b.WriteString(key.name + ": " + value + "\n")
+			continue
+		}
+		for _, line := range strings.Split(key.example, "\n") {
+			b.WriteString("# " + line + "\n")
+		}
+	}
+	return b.String()
+}
+
+// yamlString quotes s as a YAML string. JSON strings are valid YAML.
+func yamlString(s string) string {
+	quoted, _ := json.Marshal(s)
+	return string(quoted)
+}
+
+// yamlList writes items as a YAML flow sequence of strings
+func yamlList(items []string) string {
+	quoted := make([]string, 0, len(items))
+	for _, item := range items {
+		quoted = append(quoted, yamlString(item))
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// initValues are the answers config init writes into the config file
+type initValues struct {
+	baseURL      string
+	token        string
+	tokenFile    string
+	tokenCommand string
+	toolsets     []string
+	readOnly     bool
+}
+
+// initValuesFromConfig returns the values given by flags and environment
+// variables, which are the defaults of the questions
+func initValuesFromConfig() initValues {
+	return initValues{
+		baseURL:      viper.GetString("base_url"),
+		token:        viper.GetString("token"),
+		tokenFile:    viper.GetString("token_file"),
+		tokenCommand: viper.GetString("token_command"),
+		toolsets:     viper.GetStringSlice("toolsets"),
+		readOnly:     viper.GetBool("read_only"),
+	}
+}
+
+// yaml returns the values that are set, as YAML
+func (v initValues) yaml() map[string]string {
+	values := map[string]string{}
+	if v.baseURL != "" {
+		values["base_url"] = yamlString(v.baseURL)
+	}
+	if v.token != "" {
+		values["token"] = yamlString(v.token)
+	}
+	if v.tokenFile != "" {
+		values["token_file"] = yamlString(v.tokenFile)
+	}
+	if v.tokenCommand != "" {
+		values["token_command"] = yamlString(v.tokenCommand)
+	}
+	if len(v.toolsets) > 0 {
+		values["toolsets"] = yamlList(v.toolsets)
+	}
+	if v.readOnly {
+		values["read_only"] = "true"
+	}
+	return values
+}
+
+// prompter asks questions on the terminal
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// ask returns the answer to question, or current when it is left empty
+// or the input has ended
+func (p *prompter) ask(question, current string) (string, error) {
+	if current != "" {
+		_, _ = fmt.Fprintf(p.out, "%s [%s]: ", question, current)
+	} else {
+		_, _ = fmt.Fprintf(p.out, "%s: ", question)
+	}
+
+	answer, err := p.in.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return current, nil
+	}
+	return answer, nil
+}
+
+// confirm asks a yes or no question
+func (p *prompter) confirm(question string, current bool) (bool, error) {
+	for {
+		answer, err := p.ask(question+" (y/n)", map[bool]string{true: "y", false: "n"}[current])
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+	}
+}
+
+// askInitValues asks for the values of config init, defaulting to v
+func askInitValues(p *prompter, v initValues) (initValues, error) {
+	var err error
+	if v.baseURL, err = p.ask("Linkwarden URL", v.baseURL); err != nil {
+		return v, err
+	}
+
+	_, _ = fmt.Fprintln(p.out, "The token is best read from a file or a command, leave the others empty.")
+	if v.tokenFile, err = p.ask("File holding the token", v.tokenFile); err != nil {
+		return v, err
+	}
+	// Only the chosen token source is written, the others would be
+	// ignored or conflict with it
+	if v.tokenFile != "" {
+		v.tokenCommand, v.token = "", ""
+	} else if v.tokenCommand, err = p.ask("Command printing the token", v.tokenCommand); err != nil {
+		return v, err
+	}
+	if v.tokenCommand != "" {
+		v.token = ""
+	} else if v.tokenFile == "" {
+		if v.token, err = p.ask("Token, stored in plain text", v.token); err != nil {
+			return v, err
+		}
+	}
+
+	if v.toolsets, err = askToolsets(p, v.toolsets); err != nil {
+		return v, err
+	}
+
+	if v.readOnly, err = p.confirm("Read-only mode", v.readOnly); err != nil {
+		return v, err
+	}
+	return v, nil
+}
+
+// askToolsets asks for the toolsets to enable until every name is one of
+// the toolsets of a single instance
+func askToolsets(p *prompter, current []string) ([]string, error) {
+	available := linkwardenmcp.ToolsetNames(false)
+	for {
+		answer, err := p.ask("Toolsets to enable, comma-separated, empty for all",
+			strings.Join(current, ","))
+		if err != nil {
+			return nil, err
+		}
+
+		var toolsets, unknown []string
+		for _, name := range strings.Split(answer, ",") {
+			if name = strings.TrimSpace(name); name == "" {
+				continue
+			}
+			if slices.Contains(available, name) {
+				toolsets = append(toolsets, name)
+			} else {
+				unknown = append(unknown, name)
+			}
+		}
+		if len(unknown) == 0 {
+			return toolsets, nil
+		}
+
+		_, _ = fmt.Fprintf(p.out, "Unknown toolsets %s, choose from: %s\n",
+			strings.Join(unknown, ", "), strings.Join(available, ", "))
+		current = toolsets
+	}
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// configCmd groups the commands working with the config file
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "create or describe the config file",
+}
+
+// configInitCmd writes a config file with every supported key
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "write a config file with every supported key",
+	Long: "Writes " + configFileName + " to the home directory, or the --output path, " +
+		"with every supported key and a comment describing it. On a terminal it asks " +
+		"for the connection settings, otherwise it uses the given flags and " +
+		"environment variables.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString("output")
+		force, _ := cmd.Flags().GetBool("force")
+		nonInteractive, _ := cmd.Flags().GetBool("non-interactive")
+
+		if output == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return err
+			}
+			output = filepath.Join(home, configFileName)
+		}
+
+		if _, err := os.Stat(output); err == nil && !force {
+			return fmt.Errorf("%s already exists, use --force to overwrite it", output)
+		}
+
+		values := initValuesFromConfig()
+		if !nonInteractive && isTerminal(os.Stdin) {
+			p := &prompter{in: bufio.NewReader(cmd.InOrStdin()), out: cmd.OutOrStdout()}
+			var err error
+			if values, err = askInitValues(p, values); err != nil {
+				return err
+			}
+		}
+
+		// The file may hold a token, so only the user can read it
+		if err := os.WriteFile(output, []byte(renderConfig(values.yaml())), 0o600); err != nil {
+			return fmt.Errorf("failed to write config file: %w", err)
+		}
+
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Wrote %s\n", output)
+		return nil
+	},
+}
+
+// configSchemaCmd prints a JSON Schema of the config file
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "print a JSON Schema of the config file",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := json.MarshalIndent(configSchema(), "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(cmd.OutOrStdout(), string(data))
+		return err
+	},
+}
+
+func init() {
+	configInitCmd.Flags().StringP("output", "o", "", "path of the config file (default $HOME/"+configFileName+")")
+	configInitCmd.Flags().Bool("force", false, "overwrite an existing config file")
+	configInitCmd.Flags().Bool("non-interactive", false, "do not ask questions, use flags and environment variables")
+
+	configCmd.AddCommand(configInitCmd, configSchemaCmd)
+	rootCmd.AddCommand(configCmd)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigSchemaCoversFlags(t *testing.T) {
+	properties := configSchema()["properties"].(map[string]any)
+
+	rootCmd.PersistentFlags().VisitAll(func(flag *pflag.Flag) {
+		key := strings.ReplaceAll(flag.Name, "-", "_")
+		assert.Contains(t, properties, key, "flag --%s is missing from the config schema", flag.Name)
+	})
+}
+
+func TestRenderConfig(t *testing.T) {
+	values := initValues{
+		baseURL:   "https://links.example.com",
+		tokenFile: `/run/secrets/"token"`,
+		toolsets:  []string{"search", "link"},
+		readOnly:  true,
+	}
+
+	v := viper.New()
+	v.SetConfigType("yaml")
+	require.NoError(t, v.ReadConfig(strings.NewReader(renderConfig(values.yaml()))))
+
+	assert.Equal(t, "https://links.example.com", v.GetString("base_url"))
+	assert.Equal(t, `/run/secrets/"token"`, v.GetString("token_file"))
+	assert.Equal(t, []string{"search", "link"}, v.GetStringSlice("toolsets"))
+	assert.True(t, v.GetBool("read_only"))
+	assert.False(t, v.IsSet("token"))
+	assert.False(t, v.IsSet("access_policy"))
+}
+
+func TestRenderConfigExamples(t *testing.T) {
+	// Every example is valid YAML once uncommented
+	var lines []string
+	for _, line := range strings.Split(renderConfig(nil), "\n") {
+		if strings.HasPrefix(line, "# ") && strings.Contains(line, ":") && !strings.Contains(line, "`") {
+			lines = append(lines, strings.TrimPrefix(line, "# "))
+		}
+	}
+
+	v := viper.New()
+	v.SetConfigType("yaml")
+	require.NoError(t, v.ReadConfig(strings.NewReader(strings.Join(lines, "\n"))))
+
+	for _, key := range configKeys {
+		assert.True(t, v.IsSet(key.name), "example of %s", key.name)
+	}
+}
+
+func TestAskInitValues(t *testing.T) {
+	defaults := initValues{
+		token:        "plain-token",
+		tokenFile:    "/run/secrets/token",
+		tokenCommand: "pass show linkwarden",
+		toolsets:     []string{"search"},
+	}
+	ask := func(input string, v initValues) (initValues, string) {
+		var out strings.Builder
+		p := &prompter{in: bufio.NewReader(strings.NewReader(input)), out: &out}
+		v, err := askInitValues(p, v)
+		require.NoError(t, err)
+		return v, out.String()
+	}
+
+	// Choosing a token file drops the other token sources
+	v, _ := ask("https://links.example.com\n\n\nn\n", defaults)
+	assert.Equal(t, "/run/secrets/token", v.tokenFile)
+	assert.Empty(t, v.tokenCommand)
+	assert.Empty(t, v.token)
+
+	// As does choosing a command
+	defaults.tokenFile = ""
+	v, _ = ask("https://links.example.com\n\n\n\nn\n", defaults)
+	assert.Equal(t, "pass show linkwarden", v.tokenCommand)
+	assert.Empty(t, v.token)
+
+	// Unknown toolsets are asked for again
+	v, out := ask("https://links.example.com\n\n\nlink,instances\nlink,cache\nn\n", defaults)
+	assert.Contains(t, out, "Unknown toolsets instances, choose from: cache, collection, link, search, tags")
+	assert.Equal(t, []string{"link", "cache"}, v.toolsets)
+}
+
+func TestConfigSchemaToolsets(t *testing.T) {
+	properties := configSchema()["properties"].(map[string]any)
+	items := properties["toolsets"].(map[string]any)["items"].(map[string]any)
+	assert.Equal(t,
+		[]any{"cache", "collection", "instances", "link", "search", "tags"}, items["enum"])
+}
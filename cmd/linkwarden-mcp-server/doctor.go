@@ -0,0 +1,263 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/linkwardenmcp"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/log"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/observability"
+)
+
+// doctorTimeout bounds each group of requests the doctor sends to Linkwarden
+const doctorTimeout = 10 * time.Second
+
+// doctorCmd checks the configuration and the connection to Linkwarden
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "check the configuration and the connection to Linkwarden",
+	Run: func(cmd *cobra.Command, args []string) {
+		report := &doctorReport{out: cmd.OutOrStdout()}
+		runDoctor(cmd.Context(), report)
+
+		report.summary()
+		if report.failed > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// doctorReport prints the outcome of each check as it runs
+type doctorReport struct {
+	out    io.Writer
+	failed int
+	warned int
+}
+
+// pass reports a successful check
+func (r *doctorReport) pass(check, detail string) {
+	_, _ = fmt.Fprintf(r.out, "PASS  %s: %s\n", check, detail)
+}
+
+// warn reports a problem that does not stop the server from working
+func (r *doctorReport) warn(check string, err error) {
+	r.warned++
+	_, _ = fmt.Fprintf(r.out, "WARN  %s: %v\n", check, err)
+}
+
+// fail reports a failed check
+func (r *doctorReport) fail(check string, err error) {
+	r.failed++
+	_, _ = fmt.Fprintf(r.out, "FAIL  %s: %v\n", check, err)
+}
+
+// summary prints the number of problems found
+func (r *doctorReport) summary() {
+	if r.failed == 0 && r.warned == 0 {
+		_, _ = fmt.Fprintln(r.out, "\nAll checks passed")
+		return
+	}
+	_, _ = fmt.Fprintf(r.out, "\n%d failed, %d warnings\n", r.failed, r.warned)
+}
+
+// runDoctor runs every check, continuing past failures where it can
+func runDoctor(ctx context.Context, report *doctorReport) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	checkConfigFile(report)
+	checkLogLevel(report)
+	checkLogFile(report)
+
+	// Failed requests are reported by the checks, not logged
+	obs := observability.New(observability.WithLogging(log.NewDiscardLogger()))
+
+	instances, err := instancesFromConfig(obs)
+	if err != nil {
+		report.fail("instances", err)
+		return
+	}
+
+	enabled, ok := checkServerConfig(obs, instances, report)
+	if !ok {
+		return
+	}
+
+	for _, instance := range instances.List() {
+		checkInstance(ctx, instance, enabled, report)
+	}
+}
+
+// checkConfigFile reports the config file in use, if it can be parsed
+func checkConfigFile(report *doctorReport) {
+	if err := viper.ReadInConfig(); err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if errors.As(err, &notFound) {
+			report.pass("config file", "none found, using flags and environment variables")
+			return
+		}
+		report.fail("config file", err)
+		return
+	}
+	report.pass("config file", viper.ConfigFileUsed())
+}
+
+// checkLogLevel reports whether the log level is valid
+func checkLogLevel(report *doctorReport) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(viper.GetString("log_level"))); err != nil {
+		report.fail("log level", err)
+		return
+	}
+	report.pass("log level", level.String())
+}
+
+// checkLogFile reports whether the log file can be written
+func checkLogFile(report *doctorReport) {
+	path := viper.GetString("log_file")
+	if path == "" {
+		path = log.DefaultLogPath()
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		report.warn("log file", fmt.Errorf("%w, logs will go to stderr", err))
+		return
+	}
+	_ = file.Close()
+	report.pass("log file", path+" is writable")
+}
+
+// checkServerConfig builds the server from the config, which validates
+// the toolsets, tool lists, overrides, timeouts and access policy. It
+// returns the names of the enabled toolsets.
+func checkServerConfig(
+	obs *observability.Observability,
+	instances *linkwardenmcp.Instances,
+	report *doctorReport,
+) (map[string]bool, bool) {
+	customization, err := toolCustomizationFromConfig()
+	if err != nil {
+		report.fail("tool overrides", err)
+		return nil, false
+	}
+
+	toolTimeouts, err := toolTimeoutsFromConfig()
+	if err != nil {
+		report.fail("tool timeouts", err)
+		return nil, false
+	}
+
+	access, err := accessPolicyFromConfig()
+	if err != nil {
+		report.fail("access policy", err)
+		return nil, false
+	}
+
+	_, toolsetGroup, err := linkwardenmcp.NewLinkwardenMcpServer(obs, instances,
+		viper.GetStringSlice("toolsets"),
+		viper.GetBool("read_only"),
+		viper.GetBool("dynamic_toolsets"),
+		toolFilterFromConfig(),
+		customization,
+		access,
+		linkwardenmcp.NewCatalogs(viper.GetDuration("cache_ttl")),
+		toolTimeouts,
+	)
+	if err != nil {
+		report.fail("toolsets", err)
+		return nil, false
+	}
+
+	enabled := map[string]bool{}
+	var names []string
+	for name, toolset := range toolsetGroup.Toolsets {
+		if toolset.Enabled {
+			enabled[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	if len(names) == 0 {
+		report.pass("toolsets", "none enabled at startup, the model enables them on demand")
+	} else {
+		report.pass("toolsets", "enabled "+strings.Join(names, ", "))
+	}
+	return enabled, true
+}
+
+// checkInstance reports whether the instance is reachable and which
+// toolsets its token can use
+func checkInstance(
+	ctx context.Context,
+	instance linkwardenmcp.Instance,
+	enabled map[string]bool,
+	report *doctorReport,
+) {
+	prefix := "[" + instance.Name + "] "
+
+	if instance.BaseURL == "" {
+		report.fail(prefix+"base_url", errors.New("not set"))
+		return
+	}
+	if err := checkReachable(ctx, instance.BaseURL); err != nil {
+		report.fail(prefix+"base_url", err)
+		return
+	}
+	report.pass(prefix+"base_url", instance.BaseURL+" is reachable")
+
+	ctx, cancel := context.WithTimeout(ctx, doctorTimeout)
+	defer cancel()
+
+	var usable []string
+	for _, check := range linkwardenmcp.CheckToolsets(ctx, instance.Client) {
+		name := prefix + "toolset " + check.Toolset
+		switch {
+		case check.Err == nil:
+			usable = append(usable, check.Toolset)
+			report.pass(name, check.Endpoint+" works")
+		case enabled[check.Toolset]:
+			report.fail(name, fmt.Errorf("%s: %w", check.Endpoint, check.Err))
+		default:
+			report.warn(name, fmt.Errorf("%s: %w (toolset not enabled)", check.Endpoint, check.Err))
+		}
+	}
+
+	if len(usable) == 0 {
+		report.fail(prefix+"token", errors.New("cannot use any toolset"))
+		return
+	}
+	report.pass(prefix+"token", "can use "+strings.Join(usable, ", "))
+}
+
+// checkReachable reports whether a server answers at baseURL. Any HTTP
+// response counts, since the token is checked separately.
+func checkReachable(ctx context.Context, baseURL string) error {
+	ctx, cancel := context.WithTimeout(ctx, doctorTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL, nil)
+	if err != nil {
+		return fmt.Errorf("invalid base_url: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("not reachable: %w", err)
+	}
+	_ = resp.Body.Close()
+	return nil
+}
@@ -9,13 +9,18 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
+	"time"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/irfansofyana/linkwarden-mcp-server/pkg/linkwarden"
 	"github.com/irfansofyana/linkwarden-mcp-server/pkg/linkwardenmcp"
 	"github.com/irfansofyana/linkwarden-mcp-server/pkg/log"
 	"github.com/irfansofyana/linkwarden-mcp-server/pkg/mcpgo"
 	"github.com/irfansofyana/linkwarden-mcp-server/pkg/observability"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/secrets"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/toolsets"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -61,9 +66,15 @@ var stdioCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		logPath := viper.GetString("log_file")
 
+		// The level is a variable so that config reloads can change it
+		logLevel := new(slog.LevelVar)
+		if err := logLevel.UnmarshalText([]byte(viper.GetString("log_level"))); err != nil {
+			stdlog.Fatalf("invalid log level: %v", err)
+		}
+
 		config := log.NewConfig(
 			log.WithMode(log.ModeStdio),
-			log.WithLogLevel(slog.LevelInfo),
+			log.WithLogLevelVar(logLevel),
 			log.WithLogPath(logPath),
 		)
 
@@ -74,15 +85,8 @@ var stdioCmd = &cobra.Command{
 			observability.WithLogging(logger),
 		)
 
-		token := viper.GetString("token")
-		baseUrl := viper.GetString("base_url")
-
-		client, err := linkwarden.NewClientWithResponses(baseUrl, linkwarden.WithRequestEditorFn(
-			func(ctx context.Context, req *http.Request) error {
-				req.Header.Set("Authorization", "Bearer "+token)
-				return nil
-			},
-		))
+		// Get the Linkwarden instances and their clients from config
+		instances, err := instancesFromConfig(obs)
 		if err != nil {
 			obs.Logger.Errorf(ctx,
 				"error running stdio server", "error", err)
@@ -95,7 +99,43 @@ var stdioCmd = &cobra.Command{
 		// Get read-only mode from config
 		readOnly := viper.GetBool("read_only")
 
-		if err := runStdioServer(ctx, obs, client, enabledToolsets, readOnly); err != nil {
+		// Get whether toolsets are enabled on demand from config
+		dynamicToolsets := viper.GetBool("dynamic_toolsets")
+
+		// Get the tools to include or exclude within the toolsets from config
+		toolFilter := toolFilterFromConfig()
+
+		// Get the tool name prefix and description overrides from config
+		customization, err := toolCustomizationFromConfig()
+		if err != nil {
+			obs.Logger.Errorf(ctx,
+				"error running stdio server", "error", err)
+			stdlog.Fatalf("failed to run stdio server: %v", err)
+		}
+
+		// Get tool timeouts from config
+		toolTimeouts, err := toolTimeoutsFromConfig()
+		if err != nil {
+			obs.Logger.Errorf(ctx,
+				"error running stdio server", "error", err)
+			stdlog.Fatalf("failed to run stdio server: %v", err)
+		}
+
+		// Get the collections the tools may read and modify from config
+		access, err := accessPolicyFromConfig()
+		if err != nil {
+			obs.Logger.Errorf(ctx,
+				"error running stdio server", "error", err)
+			stdlog.Fatalf("failed to run stdio server: %v", err)
+		}
+
+		// Get how long collections and tags are cached from config
+		catalogs := linkwardenmcp.NewCatalogs(viper.GetDuration("cache_ttl"))
+
+		// Get whether destructive tools need confirmation from config
+		confirmation := mcpgo.WithToolConfirmation(viper.GetBool("confirm_destructive"))
+
+		if err := runStdioServer(ctx, obs, logLevel, instances, enabledToolsets, readOnly, dynamicToolsets, toolFilter, customization, access, catalogs, toolTimeouts, confirmation); err != nil {
 			obs.Logger.Errorf(ctx,
 				"error running stdio server", "error", err)
 			stdlog.Fatalf("failed to run stdio server: %v", err)
@@ -103,12 +143,191 @@ var stdioCmd = &cobra.Command{
 	},
 }
 
+// newLinkwardenClient creates a client for the Linkwarden instance at
+// baseURL that authenticates with the token of the given provider,
+// retries transient failures, fails fast while the instance is down and
+// keeps within the limits of the instance
+func newLinkwardenClient(
+	obs *observability.Observability,
+	baseURL string,
+	token secrets.Provider,
+	limits linkwardenmcp.Limits,
+) (*linkwarden.ClientWithResponses, error) {
+	if err := limits.Validate(); err != nil {
+		return nil, err
+	}
+	retryPolicy := retryPolicyFromConfig()
+	if err := retryPolicy.Validate(); err != nil {
+		return nil, err
+	}
+
+	return linkwarden.NewClientWithResponses(baseURL,
+		linkwarden.WithHTTPClient(
+			linkwardenmcp.NewLoggingDoer(obs,
+				linkwardenmcp.NewAuthDoer(secrets.NewCached(token),
+					linkwardenmcp.NewBreakerDoer(obs, breakerPolicyFromConfig(),
+						linkwardenmcp.NewRetryDoer(obs, retryPolicy,
+							linkwardenmcp.NewLimitDoer(obs, limits, http.DefaultClient))))),
+		),
+	)
+}
+
+// breakerPolicyFromConfig reads when the circuit breaker of an instance
+// opens
+func breakerPolicyFromConfig() linkwardenmcp.BreakerPolicy {
+	return linkwardenmcp.BreakerPolicy{
+		Threshold: viper.GetInt("breaker_threshold"),
+		Cooldown:  viper.GetDuration("breaker_cooldown"),
+	}
+}
+
+// limitsFromConfig reads the request limits shared by instances that do
+// not set their own
+func limitsFromConfig() (linkwardenmcp.Limits, error) {
+	var limits linkwardenmcp.Limits
+	if err := viper.UnmarshalKey("limits", &limits); err != nil {
+		return limits, fmt.Errorf("invalid limits: %w", err)
+	}
+	return limits, nil
+}
+
+// retryPolicyFromConfig reads the retry limits for Linkwarden requests
+func retryPolicyFromConfig() linkwardenmcp.RetryPolicy {
+	policy := linkwardenmcp.DefaultRetryPolicy()
+	policy.MaxRetries = viper.GetInt("max_retries")
+	policy.MaxDelay = viper.GetDuration("retry_max_delay")
+	return policy
+}
+
+// instancesFromConfig creates a client for every profile in the instances
+// map, or for base_url and token when no profiles are configured
+func instancesFromConfig(obs *observability.Observability) (*linkwardenmcp.Instances, error) {
+	limits, err := limitsFromConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if !viper.IsSet("instances") {
+		token, err := secrets.FromConfig(viper.GetString("token"),
+			viper.GetString("token_file"), viper.GetString("token_command"))
+		if err != nil {
+			return nil, err
+		}
+
+		client, err := newLinkwardenClient(obs, viper.GetString("base_url"), token, limits)
+		if err != nil {
+			return nil, err
+		}
+		return linkwardenmcp.NewInstances("", linkwardenmcp.Instance{
+			Name:    "default",
+			BaseURL: viper.GetString("base_url"),
+			Client:  client,
+		})
+	}
+
+	var configs map[string]linkwardenmcp.InstanceConfig
+	if err := viper.UnmarshalKey("instances", &configs); err != nil {
+		return nil, fmt.Errorf("invalid instances: %w", err)
+	}
+
+	instances := make([]linkwardenmcp.Instance, 0, len(configs))
+	for name, config := range configs {
+		if config.BaseURL == "" {
+			return nil, fmt.Errorf("instance %s: base_url is required", name)
+		}
+
+		token, err := secrets.FromConfig(config.Token, config.TokenFile, config.TokenCommand)
+		if err != nil {
+			return nil, fmt.Errorf("instance %s: %w", name, err)
+		}
+
+		instanceLimits := limits
+		if config.Limits != nil {
+			instanceLimits = *config.Limits
+		}
+
+		client, err := newLinkwardenClient(obs, config.BaseURL, token, instanceLimits)
+		if err != nil {
+			return nil, fmt.Errorf("instance %s: %w", name, err)
+		}
+		instances = append(instances, linkwardenmcp.Instance{
+			Name:    name,
+			BaseURL: config.BaseURL,
+			Client:  client,
+		})
+	}
+
+	return linkwardenmcp.NewInstances(viper.GetString("default_instance"), instances...)
+}
+
+// toolTimeoutsFromConfig reads the default tool timeout and the per-tool
+// overrides from the tool_timeouts map
+func toolTimeoutsFromConfig() (mcpgo.ServerOption, error) {
+	defaultTimeout := viper.GetDuration("tool_timeout")
+
+	overrides := make(map[string]time.Duration)
+	for name, value := range viper.GetStringMapString("tool_timeouts") {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout for tool %s: %w", name, err)
+		}
+		overrides[name] = timeout
+	}
+
+	return mcpgo.WithToolTimeouts(defaultTimeout, overrides), nil
+}
+
+// toolFilterFromConfig reads the tools to include or exclude within the
+// toolsets
+func toolFilterFromConfig() toolsets.ToolFilter {
+	return toolsets.ToolFilter{
+		Include: viper.GetStringSlice("tools"),
+		Exclude: viper.GetStringSlice("exclude_tools"),
+	}
+}
+
+// toolCustomizationFromConfig reads the tool name prefix and the
+// tool_overrides map of descriptions
+func toolCustomizationFromConfig() (toolsets.ToolCustomization, error) {
+	customization := toolsets.ToolCustomization{
+		Prefix: viper.GetString("tool_prefix"),
+	}
+
+	if err := viper.UnmarshalKey("tool_overrides", &customization.Overrides); err != nil {
+		return customization, fmt.Errorf("invalid tool overrides: %w", err)
+	}
+
+	return customization, nil
+}
+
+// accessPolicyFromConfig reads the access_policy section, returning a nil
+// policy that allows everything when it is missing
+func accessPolicyFromConfig() (*linkwardenmcp.AccessPolicy, error) {
+	if !viper.IsSet("access_policy") {
+		return nil, nil
+	}
+
+	var config linkwardenmcp.AccessPolicyConfig
+	if err := viper.UnmarshalKey("access_policy", &config); err != nil {
+		return nil, fmt.Errorf("invalid access policy: %w", err)
+	}
+
+	return linkwardenmcp.NewAccessPolicy(config)
+}
+
 func runStdioServer(
 	ctx context.Context,
 	obs *observability.Observability,
-	client *linkwarden.ClientWithResponses,
+	logLevel *slog.LevelVar,
+	instances *linkwardenmcp.Instances,
 	enabledToolsets []string,
 	readOnly bool,
+	dynamicToolsets bool,
+	toolFilter toolsets.ToolFilter,
+	customization toolsets.ToolCustomization,
+	access *linkwardenmcp.AccessPolicy,
+	catalogs *linkwardenmcp.Catalogs,
+	mcpOpts ...mcpgo.ServerOption,
 ) error {
 	ctx, stop := signal.NotifyContext(
 		ctx,
@@ -117,11 +336,20 @@ func runStdioServer(
 	)
 	defer stop()
 
-	srv, err := linkwardenmcp.NewLinkwardenMcpServer(obs, client, enabledToolsets, readOnly)
+	srv, toolsetGroup, err := linkwardenmcp.NewLinkwardenMcpServer(obs, instances, enabledToolsets, readOnly, dynamicToolsets, toolFilter, customization, access, catalogs, mcpOpts...)
 	if err != nil {
 		return fmt.Errorf("failed to create server: %w", err)
 	}
 
+	// Forward log entries to the client as well, at the level it asks for
+	clientLogger, err := mcpgo.NewClientLogger(obs.Logger, srv)
+	if err != nil {
+		return fmt.Errorf("failed to create client logger: %w", err)
+	}
+	obs.Logger = clientLogger
+
+	watchConfig(ctx, obs, toolsetGroup, catalogs, logLevel)
+
 	stdioSrv, err := mcpgo.NewStdioServer(srv)
 	if err != nil {
 		return fmt.Errorf("failed to create stdio server: %w", err)
@@ -153,29 +381,128 @@ func runStdioServer(
 	}
 }
 
+// configReloadDelay is how long to wait for more changes to the config
+// file before reloading it, since saving a file can take several writes
+const configReloadDelay = 200 * time.Millisecond
+
+// watchConfig reloads the parts of the config that can change while the
+// server runs whenever the config file is written
+func watchConfig(
+	ctx context.Context,
+	obs *observability.Observability,
+	toolsetGroup *toolsets.ToolsetGroup,
+	catalogs *linkwardenmcp.Catalogs,
+	logLevel *slog.LevelVar,
+) {
+	if viper.ConfigFileUsed() == "" {
+		return
+	}
+
+	var (
+		mu     sync.Mutex
+		reload *time.Timer
+	)
+	viper.OnConfigChange(func(event fsnotify.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if reload != nil {
+			reload.Stop()
+		}
+		reload = time.AfterFunc(configReloadDelay, func() {
+			reloadConfig(ctx, obs, toolsetGroup, catalogs, logLevel)
+		})
+	})
+	viper.WatchConfig()
+}
+
+// reloadConfig applies the toolset selection, read-only mode, tool filter
+// and log level of the config. An invalid config is logged and ignored,
+// so the server keeps running with the previous one.
+func reloadConfig(
+	ctx context.Context,
+	obs *observability.Observability,
+	toolsetGroup *toolsets.ToolsetGroup,
+	catalogs *linkwardenmcp.Catalogs,
+	logLevel *slog.LevelVar,
+) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(viper.GetString("log_level"))); err != nil {
+		obs.Logger.Errorf(ctx, "CONFIG_RELOAD_FAILED", "error", err)
+		return
+	}
+
+	changed, err := toolsetGroup.Reconfigure(
+		viper.GetStringSlice("toolsets"),
+		viper.GetBool("read_only"),
+		toolFilterFromConfig(),
+	)
+	if err != nil {
+		obs.Logger.Errorf(ctx, "CONFIG_RELOAD_FAILED", "error", err)
+		return
+	}
+	logLevel.Set(level)
+	catalogs.SetTTL(viper.GetDuration("cache_ttl"))
+
+	obs.Logger.Infof(ctx, "CONFIG_RELOADED",
+		"tools_changed", changed,
+		"log_level", level.String())
+}
+
 func init() {
 	cobra.OnInitialize(initConfig)
 
 	rootCmd.PersistentFlags().StringP("base-url", "b", "", "your linkwarden base url")
 	rootCmd.PersistentFlags().StringP("token", "s", "", "your linkwarden secret / token")
+	rootCmd.PersistentFlags().String("token-file", "", "path to a file holding your linkwarden token")
+	rootCmd.PersistentFlags().String("token-command", "", "command printing your linkwarden token, e.g. a password manager")
 	rootCmd.PersistentFlags().StringP("log-file", "l", "", "path to the log file")
+	rootCmd.PersistentFlags().String("log-level", "info", "log level: debug, info, warn or error")
 	rootCmd.PersistentFlags().StringSliceP("toolsets", "t", []string{}, "comma-separated list of toolsets to enable")
+	rootCmd.PersistentFlags().StringSlice("tools", []string{}, "comma-separated list of tools to enable within the toolsets, globs like get_* allowed")
+	rootCmd.PersistentFlags().StringSlice("exclude-tools", []string{}, "comma-separated list of tools to disable, globs like delete_* allowed")
+	rootCmd.PersistentFlags().String("tool-prefix", "", "prefix for every tool name, e.g. lw_")
 	rootCmd.PersistentFlags().Bool("read-only", false, "run server in read-only mode")
+	rootCmd.PersistentFlags().Bool("dynamic-toolsets", false, "expose only toolset discovery tools and enable toolsets on demand")
+	rootCmd.PersistentFlags().Duration("tool-timeout", 60*time.Second, "default timeout for a tool call, 0 to disable")
+	rootCmd.PersistentFlags().Bool("confirm-destructive", false, "ask the user to confirm destructive tool calls")
+	rootCmd.PersistentFlags().Int("max-retries", 2, "retries of a Linkwarden request that failed temporarily, 0 to disable")
+	rootCmd.PersistentFlags().Duration("retry-max-delay", 10*time.Second, "longest wait before retrying a Linkwarden request")
+	rootCmd.PersistentFlags().Duration("cache-ttl", linkwardenmcp.DefaultCatalogTTL, "how long collections and tags are cached, 0 to disable")
+	rootCmd.PersistentFlags().Int("breaker-threshold", 5, "failed Linkwarden requests in a row after which calls fail fast, 0 to disable")
+	rootCmd.PersistentFlags().Duration("breaker-cooldown", 30*time.Second, "how long calls fail fast before Linkwarden is tried again")
 
 	_ = viper.BindPFlag("base_url", rootCmd.PersistentFlags().Lookup("base-url"))
 	_ = viper.BindPFlag("token", rootCmd.PersistentFlags().Lookup("token"))
+	_ = viper.BindPFlag("token_file", rootCmd.PersistentFlags().Lookup("token-file"))
+	_ = viper.BindPFlag("token_command", rootCmd.PersistentFlags().Lookup("token-command"))
 	_ = viper.BindPFlag("log_file", rootCmd.PersistentFlags().Lookup("log-file"))
+	_ = viper.BindPFlag("log_level", rootCmd.PersistentFlags().Lookup("log-level"))
 	_ = viper.BindPFlag("toolsets", rootCmd.PersistentFlags().Lookup("toolsets"))
+	_ = viper.BindPFlag("tools", rootCmd.PersistentFlags().Lookup("tools"))
+	_ = viper.BindPFlag("exclude_tools", rootCmd.PersistentFlags().Lookup("exclude-tools"))
+	_ = viper.BindPFlag("tool_prefix", rootCmd.PersistentFlags().Lookup("tool-prefix"))
 	_ = viper.BindPFlag("read_only", rootCmd.PersistentFlags().Lookup("read-only"))
+	_ = viper.BindPFlag("dynamic_toolsets", rootCmd.PersistentFlags().Lookup("dynamic-toolsets"))
+	_ = viper.BindPFlag("tool_timeout", rootCmd.PersistentFlags().Lookup("tool-timeout"))
+	_ = viper.BindPFlag("confirm_destructive", rootCmd.PersistentFlags().Lookup("confirm-destructive"))
+	_ = viper.BindPFlag("max_retries", rootCmd.PersistentFlags().Lookup("max-retries"))
+	_ = viper.BindPFlag("retry_max_delay", rootCmd.PersistentFlags().Lookup("retry-max-delay"))
+	_ = viper.BindPFlag("cache_ttl", rootCmd.PersistentFlags().Lookup("cache-ttl"))
+	_ = viper.BindPFlag("breaker_threshold", rootCmd.PersistentFlags().Lookup("breaker-threshold"))
+	_ = viper.BindPFlag("breaker_cooldown", rootCmd.PersistentFlags().Lookup("breaker-cooldown"))
 
 	_ = viper.BindEnv("base_url", "LINKWARDEN_BASE_URL")
 	_ = viper.BindEnv("token", "LINKWARDEN_TOKEN")
+	_ = viper.BindEnv("token_file", "LINKWARDEN_TOKEN_FILE")
+	_ = viper.BindEnv("default_instance", "LINKWARDEN_INSTANCE")
 
 	// Enable environment variable reading
 	viper.AutomaticEnv()
 
 	// subcommands
 	rootCmd.AddCommand(stdioCmd)
+	rootCmd.AddCommand(doctorCmd)
 }
 
 func main() {
@@ -0,0 +1,482 @@
+package linkwardenmcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/linkwarden"
+)
+
+// Access is the level of access the server has to a collection
+type Access int
+
+const (
+	AccessNone Access = iota
+	AccessRead
+	AccessWrite
+)
+
+// accessNames maps the access levels to their names in the config file
+var accessNames = map[string]Access{
+	"none":  AccessNone,
+	"read":  AccessRead,
+	"write": AccessWrite,
+}
+
+// maxCollectionDepth bounds the walk up the collection ancestry, in case
+// the parent IDs form a cycle
+const maxCollectionDepth = 64
+
+// ParseAccess returns the access level with the given name
+func ParseAccess(name string) (Access, error) {
+	access, ok := accessNames[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return AccessNone, fmt.Errorf(
+			"invalid access %q, expected none, read or write", name)
+	}
+	return access, nil
+}
+
+// String returns the name of the access level
+func (a Access) String() string {
+	for name, access := range accessNames {
+		if access == a {
+			return name
+		}
+	}
+	return fmt.Sprintf("Access(%d)", int(a))
+}
+
+// AccessPolicyConfig is the access_policy section of the config file
+type AccessPolicyConfig struct {
+	// Default is the access to collections no rule applies to
+	Default string `mapstructure:"default"`
+
+	// Collections are the rules for individual collections
+	Collections []CollectionAccessRule `mapstructure:"collections"`
+}
+
+// CollectionAccessRule sets the access to a collection, found by ID or
+// name, and to its subcollections that have no rule of their own
+type CollectionAccessRule struct {
+	ID     int    `mapstructure:"id"`
+	Name   string `mapstructure:"name"`
+	Access string `mapstructure:"access"`
+}
+
+// accessRule is a parsed CollectionAccessRule
+type accessRule struct {
+	id     int
+	name   string
+	access Access
+}
+
+// AccessPolicy limits the collections the tools can read and modify. A
+// nil policy allows everything.
+type AccessPolicy struct {
+	defaultAccess Access
+	rules         []accessRule
+
+	// catalogs resolve collections, see withCatalogs
+	catalogs *Catalogs
+}
+
+// NewAccessPolicy creates the policy described by config. Without a
+// default, collections no rule applies to are writable.
+func NewAccessPolicy(config AccessPolicyConfig) (*AccessPolicy, error) {
+	policy := &AccessPolicy{defaultAccess: AccessWrite}
+
+	if config.Default != "" {
+		access, err := ParseAccess(config.Default)
+		if err != nil {
+			return nil, fmt.Errorf("access policy default: %w", err)
+		}
+		policy.defaultAccess = access
+	}
+
+	for i, rule := range config.Collections {
+		if rule.ID == 0 && rule.Name == "" {
+			return nil, fmt.Errorf(
+				"access policy rule %d: collection id or name is required", i+1)
+		}
+
+		access, err := ParseAccess(rule.Access)
+		if err != nil {
+			return nil, fmt.Errorf("access policy rule %d: %w", i+1, err)
+		}
+
+		policy.rules = append(policy.rules, accessRule{
+			id:     rule.ID,
+			name:   rule.Name,
+			access: access,
+		})
+	}
+
+	return policy, nil
+}
+
+// withCatalogs returns a copy of the policy that resolves collections
+// through the catalogs of a server
+func (p *AccessPolicy) withCatalogs(catalogs *Catalogs) *AccessPolicy {
+	if p == nil {
+		return nil
+	}
+	bound := *p
+	bound.catalogs = catalogs
+	return &bound
+}
+
+// restricted reports whether the policy denies anything at all, so
+// unrestricted servers skip resolving collections
+func (p *AccessPolicy) restricted() bool {
+	if p == nil {
+		return false
+	}
+	if p.defaultAccess != AccessWrite {
+		return true
+	}
+	for _, rule := range p.rules {
+		if rule.access != AccessWrite {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleFor returns the access of the first rule matching the collection
+func (p *AccessPolicy) ruleFor(collection linkwarden.Collection) (Access, bool) {
+	for _, rule := range p.rules {
+		if rule.id != 0 && rule.id == deref(collection.Id) {
+			return rule.access, true
+		}
+		if rule.name != "" && strings.EqualFold(rule.name, deref(collection.Name)) {
+			return rule.access, true
+		}
+	}
+	return AccessNone, false
+}
+
+// collectionAccess returns the access to the collection, given by the
+// nearest rule in its ancestry or else the default
+func (p *AccessPolicy) collectionAccess(
+	ctx context.Context,
+	client *linkwarden.ClientWithResponses,
+	id int,
+) (Access, error) {
+	if !p.restricted() {
+		return AccessWrite, nil
+	}
+
+	collections, err := p.catalogs.catalogFor(client).Collections(ctx)
+	if err != nil {
+		return AccessNone, err
+	}
+
+	byID := make(map[int]linkwarden.Collection, len(collections))
+	for _, collection := range collections {
+		byID[deref(collection.Id)] = collection
+	}
+
+	current := &id
+	for depth := 0; current != nil && depth < maxCollectionDepth; depth++ {
+		collection, ok := byID[*current]
+		if !ok {
+			// Collections created since the catalog was fetched are
+			// looked up directly
+			fetched, err := fetchCollection(ctx, client, *current)
+			if err != nil {
+				return AccessNone, err
+			}
+			if fetched == nil {
+				break
+			}
+			collection = *fetched
+		}
+
+		if access, ok := p.ruleFor(collection); ok {
+			return access, nil
+		}
+		current = collection.ParentId
+	}
+
+	return p.defaultAccess, nil
+}
+
+// checkCollection returns an error unless the policy grants at least need
+// on the collection
+func (p *AccessPolicy) checkCollection(
+	ctx context.Context,
+	client *linkwarden.ClientWithResponses,
+	id int,
+	need Access,
+) error {
+	access, err := p.collectionAccess(ctx, client, id)
+	if err != nil {
+		return fmt.Errorf("failed to check access to collection %d: %w", id, err)
+	}
+	if access < need {
+		return accessDenied(fmt.Sprintf("collection %d", id), access)
+	}
+	return nil
+}
+
+// checkCollectionTree is checkCollection for the collection and all of
+// its subcollections, which are affected when it is deleted
+func (p *AccessPolicy) checkCollectionTree(
+	ctx context.Context,
+	client *linkwarden.ClientWithResponses,
+	id int,
+	need Access,
+) error {
+	if !p.restricted() {
+		return nil
+	}
+
+	if err := p.checkCollection(ctx, client, id, need); err != nil {
+		return err
+	}
+
+	collections, err := p.catalogs.catalogFor(client).Collections(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check access to collection %d: %w", id, err)
+	}
+
+	for _, child := range subcollectionIDs(collections, id) {
+		if err := p.checkCollection(ctx, client, child, need); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkLink returns an error unless the policy grants at least need on the
+// collection holding the link
+func (p *AccessPolicy) checkLink(
+	ctx context.Context,
+	client *linkwarden.ClientWithResponses,
+	id int,
+	need Access,
+) error {
+	if !p.restricted() {
+		return nil
+	}
+
+	resp, err := client.GetLinkWithResponse(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to check access to link %d: %w", id, err)
+	}
+	if resp.JSON200 == nil || resp.JSON200.Response == nil {
+		return fmt.Errorf("failed to check access to link %d: %s", id, resp.Status())
+	}
+
+	return p.checkLinkIn(ctx, client, *resp.JSON200.Response, need)
+}
+
+// checkLinkIn is checkLink for a link that was already fetched
+func (p *AccessPolicy) checkLinkIn(
+	ctx context.Context,
+	client *linkwarden.ClientWithResponses,
+	link linkwarden.Link,
+	need Access,
+) error {
+	if !p.restricted() {
+		return nil
+	}
+
+	access, err := p.collectionAccess(ctx, client, deref(link.CollectionId))
+	if err != nil {
+		return fmt.Errorf("failed to check access to link %d: %w", deref(link.Id), err)
+	}
+	if access < need {
+		return accessDenied(fmt.Sprintf("link %d", deref(link.Id)), access)
+	}
+	return nil
+}
+
+// checkCollectionNamed checks the collection a link is saved to by name.
+// Linkwarden creates missing collections at the top level.
+func (p *AccessPolicy) checkCollectionNamed(
+	ctx context.Context,
+	client *linkwarden.ClientWithResponses,
+	name string,
+	need Access,
+) error {
+	if !p.restricted() {
+		return nil
+	}
+
+	collections, err := p.catalogs.catalogFor(client).Collections(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check access to collection %q: %w", name, err)
+	}
+
+	collection, err := findCollectionByName(collections, name)
+	if err != nil {
+		return p.checkTopLevel(need)
+	}
+	return p.checkCollection(ctx, client, deref(collection.Id), need)
+}
+
+// checkTopLevel returns an error unless the policy grants at least need on
+// collections without a parent, which is where new collections go
+func (p *AccessPolicy) checkTopLevel(need Access) error {
+	if !p.restricted() {
+		return nil
+	}
+
+	access := p.defaultAccess
+	if access < need {
+		return accessDenied("top-level collections", access)
+	}
+	return nil
+}
+
+// checkEverywhere returns an error unless the policy grants at least need
+// on every collection, for changes such as deleting a tag that can touch
+// links in any collection
+func (p *AccessPolicy) checkEverywhere(what string, need Access) error {
+	if !p.restricted() {
+		return nil
+	}
+
+	lowest := p.defaultAccess
+	for _, rule := range p.rules {
+		lowest = min(lowest, rule.access)
+	}
+	if lowest < need {
+		return fmt.Errorf(
+			"access denied: %s needs %s access to every collection", what, need)
+	}
+	return nil
+}
+
+// filterLinks returns the links in collections the policy grants at least
+// read access to
+func (p *AccessPolicy) filterLinks(
+	ctx context.Context,
+	client *linkwarden.ClientWithResponses,
+	links []linkwarden.Link,
+) ([]linkwarden.Link, error) {
+	if !p.restricted() {
+		return links, nil
+	}
+
+	readable := p.readableCollections(ctx, client)
+	filtered := make([]linkwarden.Link, 0, len(links))
+	for _, link := range links {
+		ok, err := readable(deref(link.CollectionId))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			filtered = append(filtered, link)
+		}
+	}
+	return filtered, nil
+}
+
+// filterCollections returns the collections the policy grants at least
+// read access to
+func (p *AccessPolicy) filterCollections(
+	ctx context.Context,
+	client *linkwarden.ClientWithResponses,
+	collections []linkwarden.Collection,
+) ([]linkwarden.Collection, error) {
+	if !p.restricted() {
+		return collections, nil
+	}
+
+	readable := p.readableCollections(ctx, client)
+	filtered := make([]linkwarden.Collection, 0, len(collections))
+	for _, collection := range collections {
+		ok, err := readable(deref(collection.Id))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			filtered = append(filtered, collection)
+		}
+	}
+	return filtered, nil
+}
+
+// readableCollections returns a function reporting whether a collection
+// is readable, resolving each collection once
+func (p *AccessPolicy) readableCollections(
+	ctx context.Context,
+	client *linkwarden.ClientWithResponses,
+) func(id int) (bool, error) {
+	seen := make(map[int]bool)
+	return func(id int) (bool, error) {
+		if readable, ok := seen[id]; ok {
+			return readable, nil
+		}
+
+		access, err := p.collectionAccess(ctx, client, id)
+		if err != nil {
+			return false, fmt.Errorf("failed to check access to collection %d: %w", id, err)
+		}
+
+		seen[id] = access >= AccessRead
+		return seen[id], nil
+	}
+}
+
+// accessDenied returns the error for a subject the policy grants too
+// little access to
+func accessDenied(subject string, access Access) error {
+	if access == AccessRead {
+		return fmt.Errorf("access denied: the access policy makes %s read-only", subject)
+	}
+	return fmt.Errorf("access denied: the access policy does not allow access to %s", subject)
+}
+
+// subcollectionIDs returns the IDs of all collections below the given one
+func subcollectionIDs(collections []linkwarden.Collection, id int) []int {
+	children := make(map[int][]int)
+	for _, collection := range collections {
+		if collection.ParentId != nil {
+			children[*collection.ParentId] = append(
+				children[*collection.ParentId], deref(collection.Id))
+		}
+	}
+
+	var ids []int
+	queue := children[id]
+	seen := map[int]bool{id: true}
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		if seen[next] {
+			continue
+		}
+		seen[next] = true
+		ids = append(ids, next)
+		queue = append(queue, children[next]...)
+	}
+	return ids
+}
+
+// fetchCollection returns the collection with the given ID, or nil if it
+// does not exist
+func fetchCollection(
+	ctx context.Context,
+	client *linkwarden.ClientWithResponses,
+	id int,
+) (*linkwarden.Collection, error) {
+	resp, err := client.GetCollectionByIdWithResponse(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection: %w", err)
+	}
+
+	if resp.StatusCode() == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.JSON200 == nil {
+		return nil, fmt.Errorf("failed to get collection: %s", resp.Status())
+	}
+
+	return resp.JSON200.Response, nil
+}
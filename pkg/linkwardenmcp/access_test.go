@@ -0,0 +1,151 @@
+package linkwardenmcp
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/linkwarden"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/log"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/mcpgo"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/observability"
+)
+
+// newAccessTestClient returns a client for a Linkwarden stub with the
+// collections Inbox (1) > Projects (2) > Archive (5), Reading (3) and
+// Private (4), link 10 in Reading and tag 7
+func newAccessTestClient(t *testing.T) *linkwarden.ClientWithResponses {
+	t.Helper()
+
+	return newStubClient(t, map[string]http.HandlerFunc{
+		"GET /api/v1/collections": respondJSON(`{"response": [
+			{"id": 1, "name": "Inbox", "parentId": null},
+			{"id": 2, "name": "Projects", "parentId": 1},
+			{"id": 3, "name": "Reading", "parentId": null},
+			{"id": 4, "name": "Private", "parentId": null},
+			{"id": 5, "name": "Archive", "parentId": 2}
+		]}`),
+		"GET /api/v1/links/10": respondJSON(`{"response": {"id": 10, "collectionId": 3}}`),
+		"GET /api/v1/tags":     respondJSON(`{"response": [{"id": 7, "name": "private-notes"}]}`),
+	})
+}
+
+func newInboxPolicy(t *testing.T) *AccessPolicy {
+	t.Helper()
+
+	policy, err := NewAccessPolicy(AccessPolicyConfig{
+		Default: "read",
+		Collections: []CollectionAccessRule{
+			{Name: "inbox", Access: "write"},
+			{ID: 4, Access: "none"},
+		},
+	})
+	require.NoError(t, err)
+	return policy
+}
+
+func TestAccessPolicyResolvesAncestry(t *testing.T) {
+	client := newAccessTestClient(t)
+	policy := newInboxPolicy(t)
+	ctx := context.Background()
+
+	expected := map[int]Access{
+		1: AccessWrite,
+		2: AccessWrite,
+		5: AccessWrite,
+		3: AccessRead,
+		4: AccessNone,
+	}
+	for id, want := range expected {
+		access, err := policy.collectionAccess(ctx, client, id)
+		require.NoError(t, err)
+		assert.Equal(t, want, access, "collection %d", id)
+	}
+}
+
+func TestAccessPolicyChecks(t *testing.T) {
+	client := newAccessTestClient(t)
+	policy := newInboxPolicy(t)
+	ctx := context.Background()
+
+	assert.NoError(t, policy.checkCollection(ctx, client, 2, AccessWrite))
+	assert.EqualError(t, policy.checkCollection(ctx, client, 3, AccessWrite),
+		"access denied: the access policy makes collection 3 read-only")
+	assert.EqualError(t, policy.checkLink(ctx, client, 10, AccessWrite),
+		"access denied: the access policy makes link 10 read-only")
+	assert.EqualError(t, policy.checkTopLevel(AccessWrite),
+		"access denied: the access policy makes top-level collections read-only")
+	assert.EqualError(t, policy.checkEverywhere("deleting a tag", AccessWrite),
+		"access denied: deleting a tag needs write access to every collection")
+	assert.NoError(t, policy.checkCollectionNamed(ctx, client, "Projects", AccessWrite))
+}
+
+func TestAccessPolicyFiltersResults(t *testing.T) {
+	client := newAccessTestClient(t)
+	policy := newInboxPolicy(t)
+	ctx := context.Background()
+
+	link := func(id, collectionID int) linkwarden.Link {
+		return linkwarden.Link{Id: &id, CollectionId: &collectionID}
+	}
+	links, err := policy.filterLinks(ctx, client, []linkwarden.Link{
+		link(1, 1), link(2, 4), link(3, 3),
+	})
+	require.NoError(t, err)
+	require.Len(t, links, 2)
+	assert.Equal(t, 1, *links[0].Id)
+	assert.Equal(t, 3, *links[1].Id)
+
+	collections, err := fetchCollections(ctx, client)
+	require.NoError(t, err)
+	visible, err := policy.filterCollections(ctx, client, collections)
+	require.NoError(t, err)
+	assert.Len(t, visible, 4)
+}
+
+func TestAccessPolicyDeniesDeletingProtectedSubcollections(t *testing.T) {
+	client := newAccessTestClient(t)
+	policy, err := NewAccessPolicy(AccessPolicyConfig{
+		Collections: []CollectionAccessRule{{Name: "Archive", Access: "read"}},
+	})
+	require.NoError(t, err)
+
+	err = policy.checkCollectionTree(context.Background(), client, 1, AccessWrite)
+	assert.EqualError(t, err, "access denied: the access policy makes collection 5 read-only")
+}
+
+func TestAccessPolicyDeniesListingTagsWithHiddenCollections(t *testing.T) {
+	client := newAccessTestClient(t)
+	obs := observability.New(observability.WithLogging(log.NewDiscardLogger()))
+
+	getAllTags := func(policy *AccessPolicy) *mcpgo.ToolResult {
+		result, err := GetAllTags(obs, client, policy, nil).GetHandler()(
+			context.Background(), mcpgo.CallToolRequest{Name: "get_all_tags"})
+		require.NoError(t, err)
+		return result
+	}
+
+	result := getAllTags(newInboxPolicy(t))
+	assert.True(t, result.IsError)
+	assert.Equal(t, "access denied: listing all tags needs read access to every collection", result.Text)
+
+	readOnly, err := NewAccessPolicy(AccessPolicyConfig{Default: "read"})
+	require.NoError(t, err)
+	result = getAllTags(readOnly)
+	assert.False(t, result.IsError)
+	assert.Contains(t, result.Text, "private-notes")
+}
+
+func TestNewAccessPolicyRejectsInvalidRules(t *testing.T) {
+	_, err := NewAccessPolicy(AccessPolicyConfig{Default: "admin"})
+	assert.EqualError(t, err,
+		`access policy default: invalid access "admin", expected none, read or write`)
+
+	_, err = NewAccessPolicy(AccessPolicyConfig{
+		Collections: []CollectionAccessRule{{Access: "write"}},
+	})
+	assert.EqualError(t, err, "access policy rule 1: collection id or name is required")
+}
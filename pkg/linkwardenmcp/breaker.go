@@ -0,0 +1,152 @@
+package linkwardenmcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/linkwarden"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/observability"
+)
+
+// BreakerPolicy sets when the circuit breaker of an instance opens
+type BreakerPolicy struct {
+	// Threshold is the number of failed requests in a row that opens the
+	// breaker, 0 disables it
+	Threshold int
+
+	// Cooldown is how long the breaker stays open before a request is
+	// let through to probe whether Linkwarden is back
+	Cooldown time.Duration
+}
+
+// UnavailableError is returned without contacting Linkwarden while the
+// circuit breaker is open
+type UnavailableError struct {
+	RetryIn time.Duration
+}
+
+func (e *UnavailableError) Error() string {
+	seconds := math.Max(math.Ceil(e.RetryIn.Seconds()), 1)
+	return fmt.Sprintf("Linkwarden unavailable, retrying in %.0fs", seconds)
+}
+
+// breakerState is the state of a circuit breaker
+type breakerState int
+
+const (
+	// breakerClosed lets every request through
+	breakerClosed breakerState = iota
+	// breakerOpen fails requests until the cooldown is over
+	breakerOpen
+	// breakerHalfOpen lets a single probe request through
+	breakerHalfOpen
+)
+
+// breakerDoer stops sending requests to a Linkwarden instance that keeps
+// failing, so tool calls fail fast instead of each waiting for a timeout
+type breakerDoer struct {
+	obs    *observability.Observability
+	policy BreakerPolicy
+	next   linkwarden.HttpRequestDoer
+	now    func() time.Time
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	retryAt  time.Time
+}
+
+// NewBreakerDoer wraps next with a circuit breaker
+func NewBreakerDoer(
+	obs *observability.Observability,
+	policy BreakerPolicy,
+	next linkwarden.HttpRequestDoer,
+) linkwarden.HttpRequestDoer {
+	return &breakerDoer{obs: obs, policy: policy, next: next, now: time.Now}
+}
+
+// Do sends the request unless the breaker is open
+func (d *breakerDoer) Do(req *http.Request) (*http.Response, error) {
+	if d.policy.Threshold <= 0 {
+		return d.next.Do(req)
+	}
+
+	ctx := req.Context()
+	probe, err := d.allow(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := d.next.Do(req)
+	d.record(ctx, probe, resp, err)
+	return resp, err
+}
+
+// allow reports whether a request may be sent, and whether it is the
+// probe of a half-open breaker
+func (d *breakerDoer) allow(ctx context.Context) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch d.state {
+	case breakerOpen:
+		now := d.now()
+		if now.Before(d.retryAt) {
+			return false, &UnavailableError{RetryIn: d.retryAt.Sub(now)}
+		}
+		d.state = breakerHalfOpen
+		d.obs.Logger.Infof(ctx, "LINKWARDEN_CIRCUIT_HALF_OPEN")
+		return true, nil
+	case breakerHalfOpen:
+		// The probe is in flight
+		return false, &UnavailableError{}
+	}
+	return false, nil
+}
+
+// record updates the breaker with the outcome of a request
+func (d *breakerDoer) record(ctx context.Context, probe bool, resp *http.Response, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch {
+	case errors.Is(err, context.Canceled) || (err != nil && ctx.Err() != nil):
+		// The caller gave up or ran out of time, such as while queued
+		// behind the limits, which says nothing about Linkwarden. A
+		// cancelled probe lets the next request probe instead.
+		if probe {
+			d.state = breakerOpen
+			d.retryAt = d.now()
+		}
+	case err != nil || isOutage(resp):
+		d.failures++
+		if probe || (d.state == breakerClosed && d.failures >= d.policy.Threshold) {
+			d.state = breakerOpen
+			d.retryAt = d.now().Add(d.policy.Cooldown)
+			d.obs.Logger.Warningf(ctx, "LINKWARDEN_CIRCUIT_OPENED",
+				"failures", d.failures,
+				"cooldown", d.policy.Cooldown.String())
+		}
+	default:
+		if d.state != breakerClosed {
+			d.obs.Logger.Infof(ctx, "LINKWARDEN_CIRCUIT_CLOSED")
+		}
+		d.state = breakerClosed
+		d.failures = 0
+	}
+}
+
+// isOutage reports whether a response shows Linkwarden or the proxy in
+// front of it is down
+func isOutage(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package linkwardenmcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/log"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/observability"
+)
+
+func TestBreakerDoerFailsFastWhileOpen(t *testing.T) {
+	status := http.StatusBadGateway
+	requests := 0
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(status)
+	}))
+	defer stub.Close()
+
+	now := time.Now()
+	obs := observability.New(observability.WithLogging(log.NewDiscardLogger()))
+	doer := NewBreakerDoer(obs, BreakerPolicy{Threshold: 2, Cooldown: 30 * time.Second},
+		http.DefaultClient).(*breakerDoer)
+	doer.now = func() time.Time { return now }
+
+	send := func() (*http.Response, error) {
+		req, err := http.NewRequest(http.MethodGet, stub.URL, nil)
+		require.NoError(t, err)
+		resp, err := doer.Do(req)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		return resp, err
+	}
+
+	for range 2 {
+		resp, err := send()
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	}
+
+	// The breaker is open, so Linkwarden is not contacted
+	now = now.Add(10 * time.Second)
+	_, err := send()
+	assert.EqualError(t, err, "Linkwarden unavailable, retrying in 20s")
+	assert.Equal(t, 2, requests)
+
+	// The probe after the cooldown fails, which opens the breaker again
+	now = now.Add(20 * time.Second)
+	_, err = send()
+	require.NoError(t, err)
+	assert.Equal(t, 3, requests)
+	_, err = send()
+	assert.EqualError(t, err, "Linkwarden unavailable, retrying in 30s")
+
+	// Once Linkwarden is back, the probe closes the breaker
+	status = http.StatusOK
+	now = now.Add(30 * time.Second)
+	for range 3 {
+		resp, err := send()
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+	assert.Equal(t, 6, requests)
+}
+
+func TestBreakerDoerIgnoresCancelledRequests(t *testing.T) {
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer stub.Close()
+
+	obs := observability.New(observability.WithLogging(log.NewDiscardLogger()))
+	doer := NewBreakerDoer(obs, BreakerPolicy{Threshold: 1, Cooldown: time.Minute}, http.DefaultClient)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, stub.URL, nil)
+	require.NoError(t, err)
+	_, err = doer.Do(req)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	req, err = http.NewRequest(http.MethodGet, stub.URL, nil)
+	require.NoError(t, err)
+	resp, err := doer.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+}
+
+func TestBreakerDoerIgnoresRequestsPastTheirDeadline(t *testing.T) {
+	release := make(chan struct{})
+	stub, arrived := newBlockingStub(t, release)
+
+	obs := observability.New(observability.WithLogging(log.NewDiscardLogger()))
+	limits := NewLimitDoer(obs, Limits{Read: Limit{MaxConcurrent: 1}}, http.DefaultClient)
+	doer := NewBreakerDoer(obs, BreakerPolicy{Threshold: 1, Cooldown: time.Minute}, limits)
+
+	sendWithDeadline := func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, stub.URL, nil)
+		require.NoError(t, err)
+		_, err = doer.Do(req)
+		return err
+	}
+
+	// Linkwarden does not answer before the deadline
+	assert.ErrorIs(t, sendWithDeadline(), context.DeadlineExceeded)
+	<-arrived
+
+	// A request queued behind the limits gives up waiting
+	held := make(chan struct{})
+	go func() {
+		defer close(held)
+		req, _ := http.NewRequest(http.MethodGet, stub.URL, nil)
+		if resp, err := limits.Do(req); err == nil {
+			_ = resp.Body.Close()
+		}
+	}()
+	<-arrived
+	err := sendWithDeadline()
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "gave up waiting for the read limit")
+
+	// Neither opened the breaker
+	close(release)
+	<-held
+	req, err := http.NewRequest(http.MethodGet, stub.URL, nil)
+	require.NoError(t, err)
+	resp, err := doer.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+}
@@ -0,0 +1,260 @@
+package linkwardenmcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/linkwarden"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/mcpgo"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/observability"
+)
+
+// DefaultCatalogTTL is how long fetched collections and tags are reused
+// unless configured otherwise
+const DefaultCatalogTTL = time.Minute
+
+// Catalogs holds the catalog of each Linkwarden client of a server. A nil
+// Catalogs caches nothing.
+type Catalogs struct {
+	ttl atomic.Int64
+
+	mu       sync.Mutex
+	byClient map[*linkwarden.ClientWithResponses]*catalog
+}
+
+// NewCatalogs returns catalogs reusing fetched collections and tags for
+// ttl, 0 disables the cache
+func NewCatalogs(ttl time.Duration) *Catalogs {
+	c := &Catalogs{byClient: make(map[*linkwarden.ClientWithResponses]*catalog)}
+	c.SetTTL(ttl)
+	return c
+}
+
+// SetTTL sets how long fetched collections and tags are reused
+func (c *Catalogs) SetTTL(ttl time.Duration) {
+	c.ttl.Store(int64(ttl))
+}
+
+// Invalidate drops the cached collections and tags of every client
+func (c *Catalogs) Invalidate() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, catalog := range c.byClient {
+		catalog.Invalidate()
+	}
+}
+
+// catalogFor returns the catalog shared by all users of the client
+func (c *Catalogs) catalogFor(client *linkwarden.ClientWithResponses) *catalog {
+	if c == nil {
+		return &catalog{client: client, ttl: new(atomic.Int64)}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.byClient[client] == nil {
+		c.byClient[client] = &catalog{client: client, ttl: &c.ttl}
+	}
+	return c.byClient[client]
+}
+
+// catalog caches the collections and tags of a Linkwarden instance, which
+// change rarely but are needed to resolve names and IDs. Tools that
+// change collections or tags invalidate it.
+type catalog struct {
+	client *linkwarden.ClientWithResponses
+	ttl    *atomic.Int64
+
+	collections cachedList[linkwarden.Collection]
+	tags        cachedList[linkwarden.Tag]
+}
+
+// Collections returns all collections, fetching them if the cached ones
+// have expired
+func (c *catalog) Collections(ctx context.Context) ([]linkwarden.Collection, error) {
+	return c.collections.get(ctx, c.ttlDuration(), func(ctx context.Context) ([]linkwarden.Collection, error) {
+		return fetchCollections(ctx, c.client)
+	})
+}
+
+// Tags returns all tags, fetching them if the cached ones have expired
+func (c *catalog) Tags(ctx context.Context) ([]linkwarden.Tag, error) {
+	return c.tags.get(ctx, c.ttlDuration(), func(ctx context.Context) ([]linkwarden.Tag, error) {
+		return fetchTags(ctx, c.client)
+	})
+}
+
+// InvalidateCollections drops the cached collections, so the next use
+// fetches them again
+func (c *catalog) InvalidateCollections() {
+	c.collections.invalidate()
+}
+
+// InvalidateTags drops the cached tags, so the next use fetches them again
+func (c *catalog) InvalidateTags() {
+	c.tags.invalidate()
+}
+
+// Invalidate drops the cached collections and tags
+func (c *catalog) Invalidate() {
+	c.InvalidateCollections()
+	c.InvalidateTags()
+}
+
+// ttlDuration returns how long fetched collections and tags are reused
+func (c *catalog) ttlDuration() time.Duration {
+	return time.Duration(c.ttl.Load())
+}
+
+// fetchCollections returns all collections
+func fetchCollections(
+	ctx context.Context,
+	client *linkwarden.ClientWithResponses,
+) ([]linkwarden.Collection, error) {
+	resp, err := client.GetAllCollectionsWithResponse(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all collections: %w", err)
+	}
+
+	if resp.JSON200 == nil {
+		return nil, fmt.Errorf("failed to get all collections: %s", resp.Status())
+	}
+
+	return deref(resp.JSON200.Response), nil
+}
+
+// fetchTags returns all tags
+func fetchTags(
+	ctx context.Context,
+	client *linkwarden.ClientWithResponses,
+) ([]linkwarden.Tag, error) {
+	resp, err := client.GetTagsWithResponse(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all tags: %w", err)
+	}
+
+	if resp.JSON200 == nil {
+		return nil, fmt.Errorf("failed to get all tags: %s", resp.Status())
+	}
+
+	return deref(resp.JSON200.Response), nil
+}
+
+// cachedList is a list that is fetched again once it expires. Concurrent
+// callers share a single fetch, which runs without holding the lock.
+type cachedList[T any] struct {
+	mu        sync.Mutex
+	items     []T
+	loaded    bool
+	fetchedAt time.Time
+	fetch     *listFetch[T]
+
+	// generation counts invalidations, so that a fetch started before
+	// one does not fill the cache with what may be outdated
+	generation int
+}
+
+// listFetch is a fetch shared by the callers waiting for it
+type listFetch[T any] struct {
+	done  chan struct{}
+	items []T
+	err   error
+}
+
+// get returns the cached list if it is younger than ttl, and otherwise
+// waits for a fetch, starting one if none is running
+func (l *cachedList[T]) get(
+	ctx context.Context,
+	ttl time.Duration,
+	fetch func(ctx context.Context) ([]T, error),
+) ([]T, error) {
+	l.mu.Lock()
+	if l.loaded && time.Since(l.fetchedAt) < ttl {
+		defer l.mu.Unlock()
+		return l.items, nil
+	}
+	f := l.fetch
+	if f == nil {
+		f = l.startFetch(ctx, fetch)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.items, f.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// startFetch starts fetching the list. l.mu must be held.
+func (l *cachedList[T]) startFetch(
+	ctx context.Context,
+	fetch func(ctx context.Context) ([]T, error),
+) *listFetch[T] {
+	f := &listFetch[T]{done: make(chan struct{})}
+	l.fetch = f
+	generation := l.generation
+
+	// The fetch is shared, so it must not stop when the first caller
+	// gives up waiting
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		items, err := fetch(ctx)
+
+		l.mu.Lock()
+		if err == nil && l.generation == generation {
+			l.items, l.loaded, l.fetchedAt = items, true, time.Now()
+		}
+		if l.fetch == f {
+			l.fetch = nil
+		}
+		l.mu.Unlock()
+
+		f.items, f.err = items, err
+		close(f.done)
+	}()
+	return f
+}
+
+// invalidate drops the cached list. Callers after it wait for a new fetch.
+func (l *cachedList[T]) invalidate() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.items, l.loaded = nil, false
+	l.fetch = nil
+	l.generation++
+}
+
+// RefreshCache returns a tool that drops the cached collections and tags
+// of every instance, for changes made outside this server
+func RefreshCache(
+	obs *observability.Observability,
+	catalogs *Catalogs,
+) mcpgo.Tool {
+	handler := func(
+		ctx context.Context,
+		req mcpgo.CallToolRequest,
+		args struct{},
+	) (string, error) {
+		catalogs.Invalidate()
+		return "Cache cleared, collections and tags are fetched again on next use", nil
+	}
+
+	return mcpgo.NewTypedTool(
+		"refresh_cache",
+		"Clears the cached collections and tags, which are reused for a "+
+			"short while. Use it when collections or tags were changed in "+
+			"Linkwarden directly and results look out of date.",
+		handler,
+		mcpgo.WithIdempotentHint(true),
+	)
+}
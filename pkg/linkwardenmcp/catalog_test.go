@@ -0,0 +1,115 @@
+package linkwardenmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/log"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/mcpgo"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/observability"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/toolsets"
+)
+
+func TestCatalogIsInvalidatedByWrites(t *testing.T) {
+	fetches := 0
+	client := newStubClient(t, map[string]http.HandlerFunc{
+		"GET /api/v1/tags": func(w http.ResponseWriter, r *http.Request) {
+			fetches++
+			respondJSON(`{"response":[{"id":1,"name":"go"}]}`)(w, r)
+		},
+		"DELETE /api/v1/tags/1":       respondJSON(`{"response":{}}`),
+		"DELETE /api/v1/links":        respondJSON(`{"response":{}}`),
+		"DELETE /api/v1/links/5":      respondJSON(`{"response":{}}`),
+		"PUT /api/v1/links/5/archive": respondJSON(`{"response":"Link is being archived."}`),
+	})
+	instances, err := NewInstances("", Instance{Name: "default", Client: client})
+	require.NoError(t, err)
+
+	obs := observability.New(observability.WithLogging(log.NewDiscardLogger()))
+	srv, _, err := NewLinkwardenMcpServer(obs, instances, []string{"tags", "link"}, false, false,
+		toolsets.ToolFilter{}, toolsets.ToolCustomization{}, nil, NewCatalogs(DefaultCatalogTTL))
+	require.NoError(t, err)
+	tools := srv.(*mcpgo.Mark3labsImpl).McpServer.ListTools()
+
+	call := func(name string, args map[string]interface{}) {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = name
+		req.Params.Arguments = args
+		result, err := tools[name].Handler(context.Background(), req)
+		require.NoError(t, err)
+		require.False(t, result.IsError, "%s failed: %v", name, result.Content)
+	}
+
+	call("get_all_tags", nil)
+	call("get_all_tags", nil)
+	assert.Equal(t, 1, fetches)
+
+	call("delete_tag_by_id", map[string]interface{}{"id": 1})
+	call("get_all_tags", nil)
+	assert.Equal(t, 2, fetches)
+
+	call("refresh_cache", nil)
+	call("get_all_tags", nil)
+	assert.Equal(t, 3, fetches)
+
+	// Deleted links change the tag usage counts, archived ones do not
+	call("delete_link_by_id", map[string]interface{}{"id": 5})
+	call("get_all_tags", nil)
+	call("delete_links", map[string]interface{}{"linkIds": []interface{}{5}})
+	call("get_all_tags", nil)
+	call("archive_link", map[string]interface{}{"id": 5})
+	call("get_all_tags", nil)
+	assert.Equal(t, 5, fetches)
+
+	// Prompts use the cache as well
+	response := srv.(*mcpgo.Mark3labsImpl).McpServer.HandleMessage(context.Background(), json.RawMessage(
+		`{"jsonrpc":"2.0","id":1,"method":"prompts/get","params":{"name":"clean_duplicate_tags"}}`))
+	require.IsType(t, mcp.JSONRPCResponse{}, response)
+	assert.Equal(t, 5, fetches)
+}
+
+func TestCatalogSharesFetchesOutsideTheLock(t *testing.T) {
+	var fetches atomic.Int32
+	started, release := make(chan struct{}), make(chan struct{})
+	client := newStubClient(t, map[string]http.HandlerFunc{
+		"GET /api/v1/tags": func(w http.ResponseWriter, r *http.Request) {
+			if fetches.Add(1) == 1 {
+				close(started)
+				<-release
+			}
+			respondJSON(`{"response":[{"id":1,"name":"go"}]}`)(w, r)
+		},
+	})
+	catalog := NewCatalogs(DefaultCatalogTTL).catalogFor(client)
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := catalog.Tags(context.Background())
+		result <- err
+	}()
+	<-started
+
+	// Another caller joins the running fetch rather than waiting for the
+	// lock or starting its own, and can give up waiting
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := catalog.Tags(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// The fetch is not cached, since it may predate the invalidation
+	catalog.InvalidateTags()
+	close(release)
+	require.NoError(t, <-result)
+
+	tags, err := catalog.Tags(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, tags, 1)
+	assert.Equal(t, int32(2), fetches.Load())
+}
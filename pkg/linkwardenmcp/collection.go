@@ -12,31 +12,32 @@ import (
 func GetAllCollections(
 	obs *observability.Observability,
 	client *linkwarden.ClientWithResponses,
+	access *AccessPolicy,
+	catalogs *Catalogs,
 ) mcpgo.Tool {
-	params := []mcpgo.ToolParameter{}
-
-	handler := func(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.ToolResult, error) {
-		client, err := getClientFromContextOrDefault(ctx, client)
+	handler := func(
+		ctx context.Context,
+		client *linkwarden.ClientWithResponses,
+		req mcpgo.CallToolRequest,
+		args struct{},
+	) (*linkwarden.CollectionsResponse, error) {
+		collections, err := catalogs.catalogFor(client).Collections(ctx)
 		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
+			return nil, err
 		}
 
-		resp, err := client.GetAllCollectionsWithResponse(ctx)
+		collections, err = access.filterCollections(ctx, client, collections)
 		if err != nil {
-			return mcpgo.NewToolResultError("Failed to get all collections: " + err.Error()), nil
-		}
-
-		if resp.JSON200 != nil {
-			return mcpgo.NewToolResultJSON(resp.JSON200)
+			return nil, err
 		}
-
-		return mcpgo.NewToolResultError("Failed to get all collections: " + resp.Status()), nil
+		return &linkwarden.CollectionsResponse{Response: &collections}, nil
 	}
 
-	return mcpgo.NewTool(
+	return newTool(
 		"get_all_collections",
 		"Gets all collections.",
-		params,
+		"get all collections",
+		client,
 		handler,
 	)
 }
@@ -45,286 +46,210 @@ func GetAllCollections(
 func GetCollectionById(
 	obs *observability.Observability,
 	client *linkwarden.ClientWithResponses,
+	access *AccessPolicy,
 ) mcpgo.Tool {
-	params := []mcpgo.ToolParameter{
-		mcpgo.WithNumber(
-			"id",
-			mcpgo.Description("The ID of the collection to retrieve."),
-		),
+	type getCollectionByIdArgs struct {
+		Id int `json:"id" description:"The ID of the collection to retrieve." required:"true"`
 	}
 
-	handler := func(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.ToolResult, error) {
-		client, err := getClientFromContextOrDefault(ctx, client)
-		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
-		}
-
-		args := make(map[string]interface{})
-
-		validator := NewValidator(&req)
-		validator.ValidateAndAddRequiredInt(args, "id")
-
-		if result, err := validator.HandleErrorsIfAny(); result != nil {
-			return result, err
+	handler := func(
+		ctx context.Context,
+		client *linkwarden.ClientWithResponses,
+		req mcpgo.CallToolRequest,
+		args getCollectionByIdArgs,
+	) (*linkwarden.CollectionResponse, error) {
+		if err := access.checkCollection(ctx, client, args.Id, AccessRead); err != nil {
+			return nil, err
 		}
 
-		id := int(args["id"].(int64))
-
-		resp, err := client.GetCollectionByIdWithResponse(ctx, id)
+		resp, err := client.GetCollectionByIdWithResponse(ctx, args.Id)
 		if err != nil {
-			return mcpgo.NewToolResultError("Failed to get collection: " + err.Error()), nil
+			return nil, requestFailed(err)
 		}
 
 		if resp.JSON200 != nil {
-			return mcpgo.NewToolResultJSON(resp.JSON200)
+			return resp.JSON200, nil
 		}
 
-		return mcpgo.NewToolResultError("Failed to get collection: " + resp.Status()), nil
+		return nil, unexpectedStatus(resp)
 	}
 
-	return mcpgo.NewTool(
+	return newTool(
 		"get_collection_by_id",
 		"Gets a collection by its ID.",
-		params,
+		"get collection",
+		client,
 		handler,
 	)
 }
 
+// createCollectionArgs are the arguments of create_collection
+type createCollectionArgs struct {
+	Name        *string `json:"name,omitempty" description:"The name of the collection."`
+	Description *string `json:"description,omitempty" description:"The description of the collection."`
+	Color       *string `json:"color,omitempty" description:"The color of the collection."`
+	Icon        *string `json:"icon,omitempty" description:"The icon of the collection."`
+	IconWeight  *string `json:"iconWeight,omitempty" description:"The weight of the collection's icon."`
+	ParentId    *int    `json:"parentId,omitempty" description:"The ID of the parent collection, if applicable."`
+}
+
 // CreateCollection returns tools for creating collections
 func CreateCollection(
 	obs *observability.Observability,
 	client *linkwarden.ClientWithResponses,
+	access *AccessPolicy,
+	catalogs *Catalogs,
 ) mcpgo.Tool {
-	params := []mcpgo.ToolParameter{
-		mcpgo.WithString(
-			"name",
-			mcpgo.Description("The name of the collection."),
-		),
-		mcpgo.WithString(
-			"description",
-			mcpgo.Description("The description of the collection."),
-		),
-		mcpgo.WithString(
-			"color",
-			mcpgo.Description("The color of the collection."),
-		),
-		mcpgo.WithString(
-			"icon",
-			mcpgo.Description("The icon of the collection."),
-		),
-		mcpgo.WithString(
-			"iconWeight",
-			mcpgo.Description("The weight of the collection's icon."),
-		),
-		mcpgo.WithNumber(
-			"parentId",
-			mcpgo.Description("The ID of the parent collection, if applicable."),
-		),
-	}
-
-	handler := func(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.ToolResult, error) {
-		client, err := getClientFromContextOrDefault(ctx, client)
-		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
+	handler := func(
+		ctx context.Context,
+		client *linkwarden.ClientWithResponses,
+		req mcpgo.CallToolRequest,
+		args createCollectionArgs,
+	) (*linkwarden.CollectionResponse, error) {
+		var err error
+		if args.ParentId != nil {
+			err = access.checkCollection(ctx, client, *args.ParentId, AccessWrite)
+		} else {
+			err = access.checkTopLevel(AccessWrite)
 		}
-
-		args := make(map[string]interface{})
-
-		validator := NewValidator(&req)
-		validator.ValidateAndAddOptionalString(args, "name")
-		validator.ValidateAndAddOptionalString(args, "description")
-		validator.ValidateAndAddOptionalString(args, "color")
-		validator.ValidateAndAddOptionalString(args, "icon")
-		validator.ValidateAndAddOptionalString(args, "iconWeight")
-		validator.ValidateAndAddOptionalInt(args, "parentId")
-
-		if result, err := validator.HandleErrorsIfAny(); result != nil {
-			return result, err
+		if err != nil {
+			return nil, err
 		}
 
-		body := &linkwarden.CreateCollectionJSONRequestBody{}
-		mappings := []ParameterMapping{
-			{Key: "name", Target: &body.Name, Type: "string"},
-			{Key: "description", Target: &body.Description, Type: "string"},
-			{Key: "color", Target: &body.Color, Type: "string"},
-			{Key: "icon", Target: &body.Icon, Type: "string"},
-			{Key: "iconWeight", Target: &body.IconWeight, Type: "string"},
-			{Key: "parentId", Target: &body.ParentId, Type: "int"},
+		body := linkwarden.CreateCollectionJSONRequestBody{
+			Name:        args.Name,
+			Description: args.Description,
+			Color:       args.Color,
+			Icon:        args.Icon,
+			IconWeight:  args.IconWeight,
+			ParentId:    args.ParentId,
 		}
-		SetOptionalParameters(args, mappings)
 
-		resp, err := client.CreateCollectionWithResponse(ctx, *body)
+		resp, err := client.CreateCollectionWithResponse(ctx, body)
 		if err != nil {
-			return mcpgo.NewToolResultError("Failed to create collection: " + err.Error()), nil
+			return nil, requestFailed(err)
 		}
 
 		if resp.JSON200 != nil {
-			return mcpgo.NewToolResultJSON(resp.JSON200)
+			catalogs.catalogFor(client).InvalidateCollections()
+			return resp.JSON200, nil
 		}
 
-		return mcpgo.NewToolResultError("Failed to create collection: " + resp.Status()), nil
+		return nil, unexpectedStatus(resp)
 	}
 
-	return mcpgo.NewTool(
+	return newTool(
 		"create_collection",
 		"Creates a new collection.",
-		params,
+		"create collection",
+		client,
 		handler,
 	)
 }
 
+// deleteCollectionByIdArgs are the arguments of delete_collection_by_id
+type deleteCollectionByIdArgs struct {
+	Id int `json:"id" description:"The ID of the collection to delete." required:"true"`
+}
+
 // DeleteCollectionById returns a tool for deleting a collection by ID
 func DeleteCollectionById(
 	obs *observability.Observability,
 	client *linkwarden.ClientWithResponses,
+	access *AccessPolicy,
+	catalogs *Catalogs,
 ) mcpgo.Tool {
-	params := []mcpgo.ToolParameter{
-		mcpgo.WithNumber(
-			"id",
-			mcpgo.Description("The ID of the collection to delete."),
-		),
-	}
-
-	handler := func(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.ToolResult, error) {
-		client, err := getClientFromContextOrDefault(ctx, client)
-		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
-		}
-
-		args := make(map[string]interface{})
-
-		validator := NewValidator(&req)
-		validator.ValidateAndAddRequiredInt(args, "id")
-
-		if result, err := validator.HandleErrorsIfAny(); result != nil {
-			return result, err
+	handler := func(
+		ctx context.Context,
+		client *linkwarden.ClientWithResponses,
+		req mcpgo.CallToolRequest,
+		args deleteCollectionByIdArgs,
+	) (string, error) {
+		// Subcollections are deleted along with the collection
+		if err := access.checkCollectionTree(ctx, client, args.Id, AccessWrite); err != nil {
+			return "", err
 		}
 
-		id := int(args["id"].(int64))
-
-		resp, err := client.DeleteCollectionByIdWithResponse(ctx, id)
+		resp, err := client.DeleteCollectionByIdWithResponse(ctx, args.Id)
 		if err != nil {
-			return mcpgo.NewToolResultError("Failed to delete collection: " + err.Error()), nil
+			return "", requestFailed(err)
 		}
 
 		if resp.StatusCode() == 200 {
-			return mcpgo.NewToolResultText("Collection deleted successfully"), nil
+			// The links of the collection took their tag usage along
+			catalogs.catalogFor(client).Invalidate()
+			return "Collection deleted successfully", nil
 		}
 
-		return mcpgo.NewToolResultError("Failed to delete collection: " + resp.Status()), nil
+		return "", unexpectedStatus(resp)
 	}
 
-	return mcpgo.NewTool(
+	return newTool(
 		"delete_collection_by_id",
 		"Deletes a collection by its ID.",
-		params,
+		"delete collection",
+		client,
 		handler,
+		mcpgo.WithConfirmation(describeCollectionDeletion(client, access)),
+		mcpgo.WithDestructiveHint(true),
+		mcpgo.WithIdempotentHint(true),
 	)
 }
 
+// publicCollectionLinksArgs are the arguments of get_public_collections_links
+type publicCollectionLinksArgs struct {
+	CollectionId int  `json:"collectionId" description:"The ID of the collection to retrieve links for." required:"true"`
+	Sort         *int `json:"sort,omitempty" description:"A numeric value to sort the results."`
+	Cursor       *int `json:"cursor,omitempty" description:"A numeric value for pagination."`
+	linkSearchArgs
+}
+
 // GetPublicCollectionsLinks returns a tool for getting public collection links
 func GetPublicCollectionsLinks(
 	obs *observability.Observability,
 	client *linkwarden.ClientWithResponses,
+	access *AccessPolicy,
 ) mcpgo.Tool {
-	params := []mcpgo.ToolParameter{
-		mcpgo.WithNumber(
-			"collectionId",
-			mcpgo.Description("The ID of the collection to retrieve links for."),
-		),
-		mcpgo.WithNumber(
-			"sort",
-			mcpgo.Description("A numeric value to sort the results."),
-		),
-		mcpgo.WithNumber(
-			"cursor",
-			mcpgo.Description("A numeric value for pagination."),
-		),
-		mcpgo.WithBoolean(
-			"pinnedOnly",
-			mcpgo.Description("Whether to return only pinned links."),
-		),
-		mcpgo.WithString(
-			"searchQueryString",
-			mcpgo.Description("A string to filter search results."),
-		),
-		mcpgo.WithBoolean(
-			"searchByName",
-			mcpgo.Description("Whether to search by name."),
-		),
-		mcpgo.WithBoolean(
-			"searchByUrl",
-			mcpgo.Description("Whether to search by URL."),
-		),
-		mcpgo.WithBoolean(
-			"searchByDescription",
-			mcpgo.Description("Whether to search by description."),
-		),
-		mcpgo.WithBoolean(
-			"searchByTextContent",
-			mcpgo.Description("Whether to search by text content."),
-		),
-		mcpgo.WithBoolean(
-			"searchByTags",
-			mcpgo.Description("Whether to search by tags."),
-		),
-	}
-
-	handler := func(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.ToolResult, error) {
-		client, err := getClientFromContextOrDefault(ctx, client)
-		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
-		}
-
-		args := make(map[string]interface{})
-
-		validator := NewValidator(&req)
-		validator.ValidateAndAddRequiredInt(args, "collectionId")
-		validator.ValidateAndAddOptionalInt(args, "sort")
-		validator.ValidateAndAddOptionalInt(args, "cursor")
-		validator.ValidateAndAddOptionalBool(args, "pinnedOnly")
-		validator.ValidateAndAddOptionalString(args, "searchQueryString")
-		validator.ValidateAndAddOptionalBool(args, "searchByName")
-		validator.ValidateAndAddOptionalBool(args, "searchByUrl")
-		validator.ValidateAndAddOptionalBool(args, "searchByDescription")
-		validator.ValidateAndAddOptionalBool(args, "searchByTextContent")
-		validator.ValidateAndAddOptionalBool(args, "searchByTags")
-
-		if result, err := validator.HandleErrorsIfAny(); result != nil {
-			return result, err
+	handler := func(
+		ctx context.Context,
+		client *linkwarden.ClientWithResponses,
+		req mcpgo.CallToolRequest,
+		args publicCollectionLinksArgs,
+	) (any, error) {
+		if err := access.checkCollection(ctx, client, args.CollectionId, AccessRead); err != nil {
+			return nil, err
 		}
 
-		params := &linkwarden.GetApiV1PublicCollectionsLinksParams{}
-		mappings := []ParameterMapping{
-			{Key: "collectionId", Target: &params.CollectionId, Type: "int"},
-			{Key: "sort", Target: &params.Sort, Type: "int"},
-			{Key: "cursor", Target: &params.Cursor, Type: "int"},
-			{Key: "pinnedOnly", Target: &params.PinnedOnly, Type: "bool"},
-			{Key: "searchQueryString", Target: &params.SearchQueryString, Type: "string"},
-			{Key: "searchByName", Target: &params.SearchByName, Type: "bool"},
-			{Key: "searchByUrl", Target: &params.SearchByUrl, Type: "bool"},
-			{Key: "searchByDescription", Target: &params.SearchByDescription, Type: "bool"},
-			{Key: "searchByTextContent", Target: &params.SearchByTextContent, Type: "bool"},
-			{Key: "searchByTags", Target: &params.SearchByTags, Type: "bool"},
+		params := &linkwarden.GetApiV1PublicCollectionsLinksParams{
+			CollectionId:        args.CollectionId,
+			Sort:                args.Sort,
+			Cursor:              args.Cursor,
+			PinnedOnly:          args.PinnedOnly,
+			SearchQueryString:   args.SearchQueryString,
+			SearchByName:        args.SearchByName,
+			SearchByUrl:         args.SearchByUrl,
+			SearchByDescription: args.SearchByDescription,
+			SearchByTextContent: args.SearchByTextContent,
+			SearchByTags:        args.SearchByTags,
 		}
-		SetOptionalParameters(args, mappings)
 
 		resp, err := client.GetApiV1PublicCollectionsLinksWithResponse(ctx, params)
 		if err != nil {
-			return mcpgo.NewToolResultError("Failed to get public collection links: " + err.Error()), nil
+			return nil, requestFailed(err)
 		}
 
 		if resp.JSON200 != nil {
-			return mcpgo.NewToolResultJSON(resp.JSON200)
+			return resp.JSON200, nil
 		}
 
-		return mcpgo.NewToolResultError("Failed to get public collection links: " + resp.Status()), nil
+		return nil, unexpectedStatus(resp)
 	}
 
-	return mcpgo.NewTool(
+	return newTool(
 		"get_public_collections_links",
 		"Gets links from a public collection.",
-		params,
+		"get public collection links",
+		client,
 		handler,
 	)
 }
@@ -333,51 +258,43 @@ func GetPublicCollectionsLinks(
 func GetPublicCollectionsTags(
 	obs *observability.Observability,
 	client *linkwarden.ClientWithResponses,
+	access *AccessPolicy,
 ) mcpgo.Tool {
-	params := []mcpgo.ToolParameter{
-		mcpgo.WithNumber(
-			"collectionId",
-			mcpgo.Description("The ID of the collection to retrieve tags for."),
-		),
+	type publicCollectionTagsArgs struct {
+		CollectionId int `json:"collectionId" description:"The ID of the collection to retrieve tags for." required:"true"`
 	}
 
-	handler := func(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.ToolResult, error) {
-		client, err := getClientFromContextOrDefault(ctx, client)
-		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
-		}
-
-		args := make(map[string]interface{})
-
-		validator := NewValidator(&req)
-		validator.ValidateAndAddRequiredInt(args, "collectionId")
-
-		if result, err := validator.HandleErrorsIfAny(); result != nil {
-			return result, err
+	handler := func(
+		ctx context.Context,
+		client *linkwarden.ClientWithResponses,
+		req mcpgo.CallToolRequest,
+		args publicCollectionTagsArgs,
+	) (any, error) {
+		if err := access.checkCollection(ctx, client, args.CollectionId, AccessRead); err != nil {
+			return nil, err
 		}
 
-		params := &linkwarden.GetApiV1PublicCollectionsTagsParams{}
-		mappings := []ParameterMapping{
-			{Key: "collectionId", Target: &params.CollectionId, Type: "int"},
+		params := &linkwarden.GetApiV1PublicCollectionsTagsParams{
+			CollectionId: args.CollectionId,
 		}
-		SetOptionalParameters(args, mappings)
 
 		resp, err := client.GetApiV1PublicCollectionsTagsWithResponse(ctx, params)
 		if err != nil {
-			return mcpgo.NewToolResultError("Failed to get public collection tags: " + err.Error()), nil
+			return nil, requestFailed(err)
 		}
 
 		if resp.JSON200 != nil {
-			return mcpgo.NewToolResultJSON(resp.JSON200)
+			return resp.JSON200, nil
 		}
 
-		return mcpgo.NewToolResultError("Failed to get public collection tags: " + resp.Status()), nil
+		return nil, unexpectedStatus(resp)
 	}
 
-	return mcpgo.NewTool(
+	return newTool(
 		"get_public_collections_tags",
 		"Gets tags from a public collection.",
-		params,
+		"get public collection tags",
+		client,
 		handler,
 	)
 }
@@ -386,47 +303,39 @@ func GetPublicCollectionsTags(
 func GetPublicCollectionById(
 	obs *observability.Observability,
 	client *linkwarden.ClientWithResponses,
+	access *AccessPolicy,
 ) mcpgo.Tool {
-	params := []mcpgo.ToolParameter{
-		mcpgo.WithNumber(
-			"id",
-			mcpgo.Description("The ID of the public collection to retrieve."),
-		),
+	type publicCollectionByIdArgs struct {
+		Id int `json:"id" description:"The ID of the public collection to retrieve." required:"true"`
 	}
 
-	handler := func(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.ToolResult, error) {
-		client, err := getClientFromContextOrDefault(ctx, client)
-		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
-		}
-
-		args := make(map[string]interface{})
-
-		validator := NewValidator(&req)
-		validator.ValidateAndAddRequiredInt(args, "id")
-
-		if result, err := validator.HandleErrorsIfAny(); result != nil {
-			return result, err
+	handler := func(
+		ctx context.Context,
+		client *linkwarden.ClientWithResponses,
+		req mcpgo.CallToolRequest,
+		args publicCollectionByIdArgs,
+	) (*linkwarden.CollectionResponse, error) {
+		if err := access.checkCollection(ctx, client, args.Id, AccessRead); err != nil {
+			return nil, err
 		}
 
-		id := int(args["id"].(int64))
-
-		resp, err := client.GetApiV1PublicCollectionsIdWithResponse(ctx, id)
+		resp, err := client.GetApiV1PublicCollectionsIdWithResponse(ctx, args.Id)
 		if err != nil {
-			return mcpgo.NewToolResultError("Failed to get public collection: " + err.Error()), nil
+			return nil, requestFailed(err)
 		}
 
 		if resp.JSON200 != nil {
-			return mcpgo.NewToolResultJSON(resp.JSON200)
+			return resp.JSON200, nil
 		}
 
-		return mcpgo.NewToolResultError("Failed to get public collection: " + resp.Status()), nil
+		return nil, unexpectedStatus(resp)
 	}
 
-	return mcpgo.NewTool(
+	return newTool(
 		"get_public_collection_by_id",
 		"Gets a public collection by its ID.",
-		params,
+		"get public collection",
+		client,
 		handler,
 	)
 }
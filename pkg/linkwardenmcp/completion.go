@@ -0,0 +1,120 @@
+package linkwardenmcp
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/linkwarden"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/mcpgo"
+)
+
+// completionCandidate is a value that can be suggested, along with the
+// texts it can be matched by
+type completionCandidate struct {
+	value string
+	texts []string
+}
+
+// Match quality of a candidate, better matches have lower ranks
+const (
+	matchPrefix = iota
+	matchSubstring
+	matchFuzzy
+	matchNone
+)
+
+// matchRank returns how well text matches the typed value
+func matchRank(text, value string) int {
+	text = strings.ToLower(text)
+	value = strings.ToLower(value)
+
+	switch {
+	case strings.HasPrefix(text, value):
+		return matchPrefix
+	case strings.Contains(text, value):
+		return matchSubstring
+	case isSubsequence(value, text):
+		return matchFuzzy
+	default:
+		return matchNone
+	}
+}
+
+// isSubsequence reports whether all characters of value appear in text
+// in order
+func isSubsequence(value, text string) bool {
+	runes := []rune(value)
+	i := 0
+	for _, r := range text {
+		if i < len(runes) && runes[i] == r {
+			i++
+		}
+	}
+	return i == len(runes)
+}
+
+// completeCandidates returns the values of the candidates matching value,
+// best matches first
+func completeCandidates(candidates []completionCandidate, value string) []string {
+	type ranked struct {
+		value string
+		rank  int
+	}
+
+	matches := []ranked{}
+	for _, candidate := range candidates {
+		best := matchNone
+		for _, text := range candidate.texts {
+			best = min(best, matchRank(text, value))
+		}
+		if best != matchNone {
+			matches = append(matches, ranked{value: candidate.value, rank: best})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].rank < matches[j].rank
+	})
+
+	values := make([]string, 0, len(matches))
+	for _, match := range matches {
+		values = append(values, match.value)
+	}
+	return values
+}
+
+// completeCollectionNames suggests collection names matching an ID or name
+func completeCollectionNames(
+	client *linkwarden.ClientWithResponses,
+	access *AccessPolicy,
+	catalogs *Catalogs,
+) mcpgo.ArgumentCompleter {
+	return func(ctx context.Context, value string) ([]string, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+
+		collections, err := catalogs.catalogFor(client).Collections(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		collections, err = access.filterCollections(ctx, client, collections)
+		if err != nil {
+			return nil, err
+		}
+
+		candidates := make([]completionCandidate, 0, len(collections))
+		for _, collection := range collections {
+			name := deref(collection.Name)
+			candidates = append(candidates, completionCandidate{
+				value: name,
+				texts: []string{strconv.Itoa(deref(collection.Id)), name},
+			})
+		}
+		return completeCandidates(candidates, value), nil
+	}
+}
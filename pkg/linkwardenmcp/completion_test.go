@@ -0,0 +1,22 @@
+package linkwardenmcp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompleteCandidatesRanksPrefixBeforeSubstringAndFuzzy(t *testing.T) {
+	candidates := []completionCandidate{
+		{value: "1", texts: []string{"1", "Gardening Notes"}},
+		{value: "2", texts: []string{"2", "Go Recipes"}},
+		{value: "3", texts: []string{"3", "Golang"}},
+		{value: "4", texts: []string{"4", "Lego"}},
+		{value: "5", texts: []string{"5", "Work"}},
+	}
+
+	assert.Equal(t, []string{"2", "3", "4", "1"}, completeCandidates(candidates, "go"))
+	assert.Equal(t, []string{"3"}, completeCandidates(candidates, "GLG"))
+	assert.Equal(t, []string{"5"}, completeCandidates(candidates, "5"))
+	assert.Empty(t, completeCandidates(candidates, "xyz"))
+}
@@ -0,0 +1,132 @@
+package linkwardenmcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/linkwarden"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/mcpgo"
+)
+
+// maxConfirmationLinkIDs is how many link IDs a confirmation lists
+const maxConfirmationLinkIDs = 10
+
+// describer describes a tool call for confirmation with the client of the
+// instance the call is for
+type describer[Args any] func(
+	ctx context.Context,
+	client *linkwarden.ClientWithResponses,
+	args Args) (string, error)
+
+// newDescriber creates a typed describer that runs against the client of
+// the call's instance. Like newTool, failed Linkwarden requests are
+// reported as "Failed to <action>: <cause>".
+func newDescriber[Args any](
+	action string,
+	client *linkwarden.ClientWithResponses,
+	describe describer[Args],
+) mcpgo.ConfirmationDescriber {
+	return mcpgo.NewTypedConfirmation(
+		func(ctx context.Context, req mcpgo.CallToolRequest, args Args) (string, error) {
+			client, err := getClientFromContextOrDefault(ctx, client)
+			if err != nil {
+				return "", err
+			}
+
+			description, err := describe(ctx, client, args)
+			var reqErr *requestError
+			if errors.As(err, &reqErr) {
+				err = &actionError{action: action, err: err}
+			}
+			return description, err
+		})
+}
+
+// describeCollectionDeletion describes the collection about to be deleted
+// along with how many links it holds. The access policy is checked first,
+// so a denied caller does not learn about the collection.
+func describeCollectionDeletion(
+	client *linkwarden.ClientWithResponses,
+	access *AccessPolicy,
+) mcpgo.ConfirmationDescriber {
+	return newDescriber("get collection", client,
+		func(ctx context.Context, client *linkwarden.ClientWithResponses, args deleteCollectionByIdArgs) (string, error) {
+			if err := access.checkCollectionTree(ctx, client, args.Id, AccessWrite); err != nil {
+				return "", err
+			}
+
+			resp, err := client.GetCollectionByIdWithResponse(ctx, args.Id)
+			if err != nil {
+				return "", requestFailed(err)
+			}
+			if resp.JSON200 == nil || resp.JSON200.Response == nil {
+				return "", unexpectedStatus(resp)
+			}
+
+			collection := resp.JSON200.Response
+			links := 0
+			if collection.UnderscoreCount != nil {
+				links = deref(collection.UnderscoreCount.Links)
+			}
+
+			return fmt.Sprintf("Delete collection %q (ID %d) and its %d links?",
+				deref(collection.Name), args.Id, links), nil
+		})
+}
+
+// describeLinksDeletion describes how many links are about to be deleted
+func describeLinksDeletion() mcpgo.ConfirmationDescriber {
+	return mcpgo.NewTypedConfirmation(
+		func(ctx context.Context, req mcpgo.CallToolRequest, args deleteLinksArgs) (string, error) {
+			ids := make([]string, 0, maxConfirmationLinkIDs)
+			for _, id := range args.LinkIds[:min(len(args.LinkIds), maxConfirmationLinkIDs)] {
+				ids = append(ids, strconv.Itoa(id))
+			}
+			if len(args.LinkIds) > maxConfirmationLinkIDs {
+				ids = append(ids, "...")
+			}
+
+			return fmt.Sprintf("Delete %d links (IDs %s)?",
+				len(args.LinkIds), strings.Join(ids, ", ")), nil
+		})
+}
+
+// describeTagDeletion describes the tag about to be deleted along with how
+// many links use it. The access policy is checked first, since the usage
+// counts span every collection.
+func describeTagDeletion(
+	client *linkwarden.ClientWithResponses,
+	access *AccessPolicy,
+) mcpgo.ConfirmationDescriber {
+	return newDescriber("get tags", client,
+		func(ctx context.Context, client *linkwarden.ClientWithResponses, args deleteTagByIdArgs) (string, error) {
+			if err := access.checkEverywhere("deleting a tag", AccessWrite); err != nil {
+				return "", err
+			}
+
+			// Fetch the tags directly, the usage counts in the catalog may be stale
+			tags, err := fetchTags(ctx, client)
+			if err != nil {
+				return "", err
+			}
+
+			for _, tag := range tags {
+				if deref(tag.Id) != args.Id {
+					continue
+				}
+
+				links := 0
+				if tag.UnderscoreCount != nil {
+					links = deref(tag.UnderscoreCount.Links)
+				}
+
+				return fmt.Sprintf("Delete tag %q (ID %d), which is used by %d links?",
+					deref(tag.Name), args.Id, links), nil
+			}
+
+			return "", fmt.Errorf("tag %d not found", args.Id)
+		})
+}
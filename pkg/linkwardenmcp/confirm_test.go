@@ -0,0 +1,110 @@
+package linkwardenmcp
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/linkwarden"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/mcpgo"
+)
+
+func newDescriberTestClient(t *testing.T) *linkwarden.ClientWithResponses {
+	t.Helper()
+
+	return newStubClient(t, map[string]http.HandlerFunc{
+		"GET /api/v1/collections/3": respondJSON(
+			`{"response":{"id":3,"name":"Reading","_count":{"links":12}}}`),
+		"GET /api/v1/tags": respondJSON(
+			`{"response":[{"id":1,"name":"go","_count":{"links":4}},{"id":2,"name":"rust"}]}`),
+	})
+}
+
+func describe(
+	t *testing.T, describer mcpgo.ConfirmationDescriber, args map[string]interface{},
+) (string, *mcpgo.ToolResult) {
+	t.Helper()
+	description, result, err := describer(context.Background(), mcpgo.CallToolRequest{Arguments: args})
+	require.NoError(t, err)
+	return description, result
+}
+
+func TestDescribeCollectionDeletion(t *testing.T) {
+	describer := describeCollectionDeletion(newDescriberTestClient(t), nil)
+
+	description, result := describe(t, describer, map[string]interface{}{"id": float64(3)})
+	assert.Nil(t, result)
+	assert.Equal(t, `Delete collection "Reading" (ID 3) and its 12 links?`, description)
+
+	_, result = describe(t, describer, map[string]interface{}{"id": float64(4)})
+	require.NotNil(t, result)
+	assert.Equal(t, "Failed to get collection: 404 Not Found", result.Text)
+}
+
+func TestDescribeCollectionDeletionChecksAccessFirst(t *testing.T) {
+	fetched := 0
+	client := newStubClient(t, map[string]http.HandlerFunc{
+		"GET /api/v1/collections": respondJSON(
+			`{"response":[{"id":3,"name":"Reading","parentId":null}]}`),
+		"GET /api/v1/collections/3": func(w http.ResponseWriter, r *http.Request) {
+			fetched++
+			respondJSON(`{"response":{"id":3,"name":"Reading","_count":{"links":12}}}`)(w, r)
+		},
+	})
+	policy, err := NewAccessPolicy(AccessPolicyConfig{
+		Default:     "write",
+		Collections: []CollectionAccessRule{{ID: 3, Access: "none"}},
+	})
+	require.NoError(t, err)
+
+	_, result := describe(t, describeCollectionDeletion(client, policy),
+		map[string]interface{}{"id": float64(3)})
+	require.NotNil(t, result)
+	assert.Equal(t, "access denied: the access policy does not allow access to collection 3", result.Text)
+	assert.Zero(t, fetched)
+}
+
+func TestDescribeLinksDeletion(t *testing.T) {
+	describer := describeLinksDeletion()
+
+	description, result := describe(t, describer, map[string]interface{}{
+		"linkIds": []interface{}{float64(1), float64(2)},
+	})
+	assert.Nil(t, result)
+	assert.Equal(t, "Delete 2 links (IDs 1, 2)?", description)
+
+	ids := make([]interface{}, 12)
+	for i := range ids {
+		ids[i] = float64(i + 1)
+	}
+	description, result = describe(t, describer, map[string]interface{}{"linkIds": ids})
+	assert.Nil(t, result)
+	assert.Equal(t, "Delete 12 links (IDs 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, ...)?", description)
+}
+
+func TestDescribeTagDeletion(t *testing.T) {
+	client := newDescriberTestClient(t)
+	describer := describeTagDeletion(client, nil)
+
+	description, result := describe(t, describer, map[string]interface{}{"id": float64(1)})
+	assert.Nil(t, result)
+	assert.Equal(t, `Delete tag "go" (ID 1), which is used by 4 links?`, description)
+
+	description, result = describe(t, describer, map[string]interface{}{"id": float64(2)})
+	assert.Nil(t, result)
+	assert.Equal(t, `Delete tag "rust" (ID 2), which is used by 0 links?`, description)
+
+	_, result = describe(t, describer, map[string]interface{}{"id": float64(9)})
+	require.NotNil(t, result)
+	assert.Equal(t, "tag 9 not found", result.Text)
+
+	// Usage counts span every collection, so a restricted caller is not told
+	readOnly, err := NewAccessPolicy(AccessPolicyConfig{Default: "read"})
+	require.NoError(t, err)
+	_, result = describe(t, describeTagDeletion(client, readOnly), map[string]interface{}{"id": float64(1)})
+	require.NotNil(t, result)
+	assert.Equal(t, "access denied: deleting a tag needs write access to every collection", result.Text)
+}
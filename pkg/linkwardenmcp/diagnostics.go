@@ -0,0 +1,89 @@
+package linkwardenmcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/linkwarden"
+)
+
+// ToolsetCheck is the outcome of calling an endpoint a toolset relies on
+// with the configured token. Err is nil when the call succeeded.
+type ToolsetCheck struct {
+	Toolset  string
+	Endpoint string
+	Err      error
+}
+
+// toolsetProbe calls a read-only endpoint used by the tools of a toolset
+type toolsetProbe struct {
+	toolset  string
+	endpoint string
+	call     func(ctx context.Context, client *linkwarden.ClientWithResponses) (*http.Response, error)
+}
+
+// toolsetProbes lists one probe per Linkwarden toolset
+var toolsetProbes = []toolsetProbe{
+	{
+		toolset:  "search",
+		endpoint: "GET /api/v1/search",
+		call: func(ctx context.Context, client *linkwarden.ClientWithResponses) (*http.Response, error) {
+			return client.SearchLinks(ctx, &linkwarden.SearchLinksParams{})
+		},
+	},
+	{
+		toolset:  "collection",
+		endpoint: "GET /api/v1/collections",
+		call: func(ctx context.Context, client *linkwarden.ClientWithResponses) (*http.Response, error) {
+			return client.GetAllCollections(ctx)
+		},
+	},
+	{
+		toolset:  "link",
+		endpoint: "GET /api/v1/links",
+		call: func(ctx context.Context, client *linkwarden.ClientWithResponses) (*http.Response, error) {
+			return client.GetApiV1Links(ctx, &linkwarden.GetApiV1LinksParams{})
+		},
+	},
+	{
+		toolset:  "tags",
+		endpoint: "GET /api/v1/tags",
+		call: func(ctx context.Context, client *linkwarden.ClientWithResponses) (*http.Response, error) {
+			return client.GetTags(ctx)
+		},
+	},
+}
+
+// CheckToolsets calls a read-only endpoint of every toolset to find out
+// which toolsets the client's token can use
+func CheckToolsets(ctx context.Context, client *linkwarden.ClientWithResponses) []ToolsetCheck {
+	checks := make([]ToolsetCheck, 0, len(toolsetProbes))
+	for _, probe := range toolsetProbes {
+		checks = append(checks, ToolsetCheck{
+			Toolset:  probe.toolset,
+			Endpoint: probe.endpoint,
+			Err:      runProbe(ctx, client, probe),
+		})
+	}
+	return checks
+}
+
+// runProbe calls the endpoint of the probe and explains a failure
+func runProbe(ctx context.Context, client *linkwarden.ClientWithResponses, probe toolsetProbe) error {
+	resp, err := probe.call(ctx, client)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return fmt.Errorf("token rejected (%s), it may be wrong or expired", resp.Status)
+	case resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("token not allowed to use this endpoint (%s)", resp.Status)
+	case resp.StatusCode >= http.StatusBadRequest:
+		return fmt.Errorf("unexpected response %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package linkwardenmcp
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckToolsetsExplainsFailures(t *testing.T) {
+	status := func(code int) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(code) }
+	}
+	client := newStubClient(t, map[string]http.HandlerFunc{
+		"/":              status(http.StatusOK),
+		"/api/v1/links":  status(http.StatusForbidden),
+		"/api/v1/links/": status(http.StatusForbidden),
+		"/api/v1/tags":   status(http.StatusUnauthorized),
+		"/api/v1/tags/":  status(http.StatusUnauthorized),
+	})
+
+	failures := map[string]string{}
+	for _, check := range CheckToolsets(context.Background(), client) {
+		if check.Err != nil {
+			failures[check.Toolset] = check.Err.Error()
+		}
+	}
+
+	assert.Equal(t, map[string]string{
+		"link": "token not allowed to use this endpoint (403 Forbidden)",
+		"tags": "token rejected (401 Unauthorized), it may be wrong or expired",
+	}, failures)
+}
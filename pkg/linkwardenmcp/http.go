@@ -0,0 +1,119 @@
+package linkwardenmcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/linkwarden"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/observability"
+)
+
+// loggingDoer logs a warning for every Linkwarden request that fails, so
+// problems such as an expired token show up in the client's log as well
+type loggingDoer struct {
+	obs  *observability.Observability
+	next linkwarden.HttpRequestDoer
+}
+
+// NewLoggingDoer wraps next with warnings for failed Linkwarden requests
+func NewLoggingDoer(
+	obs *observability.Observability,
+	next linkwarden.HttpRequestDoer,
+) linkwarden.HttpRequestDoer {
+	return &loggingDoer{obs: obs, next: next}
+}
+
+// Do sends the request and logs unsuccessful responses
+func (d *loggingDoer) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	resp, err := d.next.Do(req)
+	if err != nil {
+		d.obs.Logger.Warningf(ctx, "LINKWARDEN_REQUEST_FAILED",
+			"method", req.Method,
+			"path", req.URL.Path,
+			"error", err)
+		return resp, err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		d.obs.Logger.Warningf(ctx, "LINKWARDEN_REQUEST_FAILED",
+			"method", req.Method,
+			"path", req.URL.Path,
+			"status", resp.Status)
+	}
+
+	return resp, nil
+}
+
+// TokenSource provides the Linkwarden token and reads it again when the
+// token in use is rejected
+type TokenSource interface {
+	Secret(ctx context.Context) (string, error)
+	Refresh(ctx context.Context) (bool, error)
+}
+
+// authDoer authenticates Linkwarden requests with the token of a source.
+// When Linkwarden rejects the token, the source is read again and the
+// request retried once if the token changed, so a rotated token is picked
+// up without a restart.
+type authDoer struct {
+	token TokenSource
+	next  linkwarden.HttpRequestDoer
+}
+
+// NewAuthDoer wraps next with bearer token authentication
+func NewAuthDoer(
+	token TokenSource,
+	next linkwarden.HttpRequestDoer,
+) linkwarden.HttpRequestDoer {
+	return &authDoer{token: token, next: next}
+}
+
+// Do sends the request with the current token
+func (d *authDoer) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	token, err := d.token.Secret(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the Linkwarden token: %w", err)
+	}
+
+	resp, err := d.next.Do(withToken(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// A request whose body was consumed cannot be sent again
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+
+	changed, err := d.token.Refresh(ctx)
+	if err != nil || !changed {
+		return resp, nil
+	}
+
+	token, err = d.token.Secret(ctx)
+	if err != nil {
+		return resp, nil
+	}
+
+	retry := withToken(req, token)
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return resp, nil
+		}
+	}
+
+	_ = resp.Body.Close()
+	return d.next.Do(retry)
+}
+
+// withToken returns a copy of the request authenticated with token
+func withToken(req *http.Request, token string) *http.Request {
+	authed := req.Clone(req.Context())
+	authed.Header.Set("Authorization", "Bearer "+token)
+	return authed
+}
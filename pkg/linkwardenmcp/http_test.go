@@ -0,0 +1,53 @@
+package linkwardenmcp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/secrets"
+)
+
+func TestAuthDoerRetriesWithRotatedToken(t *testing.T) {
+	var bodies []string
+	stub := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			if r.Header.Get("Authorization") != "Bearer rotated" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		},
+	))
+	defer stub.Close()
+
+	token := "expired"
+	doer := NewAuthDoer(
+		secrets.NewCached(secrets.ProviderFunc(func(ctx context.Context) (string, error) {
+			return token, nil
+		})),
+		http.DefaultClient,
+	)
+
+	send := func() *http.Response {
+		req, err := http.NewRequest(http.MethodPost, stub.URL, strings.NewReader("link"))
+		require.NoError(t, err)
+		resp, err := doer.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		return resp
+	}
+
+	// The token has not changed, so the rejection is returned as is
+	assert.Equal(t, http.StatusUnauthorized, send().StatusCode)
+
+	token = "rotated"
+	assert.Equal(t, http.StatusOK, send().StatusCode)
+	assert.Equal(t, []string{"link", "link", "link"}, bodies)
+}
@@ -0,0 +1,178 @@
+package linkwardenmcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/contextkey"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/linkwarden"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/mcpgo"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/observability"
+)
+
+// instanceParameter is the tool argument selecting the Linkwarden instance
+const instanceParameter = "instance"
+
+// InstanceConfig is a profile in the instances section of the config file
+type InstanceConfig struct {
+	BaseURL      string `mapstructure:"base_url"`
+	Token        string `mapstructure:"token"`
+	TokenFile    string `mapstructure:"token_file"`
+	TokenCommand string `mapstructure:"token_command"`
+
+	// Limits replace the top-level limits for this instance
+	Limits *Limits `mapstructure:"limits"`
+}
+
+// Instance is a Linkwarden instance the tools can work with
+type Instance struct {
+	Name    string
+	BaseURL string
+	Client  *linkwarden.ClientWithResponses
+}
+
+// Instances are the Linkwarden instances of the server. Tools use the
+// default instance unless a call names another one.
+type Instances struct {
+	byName      map[string]Instance
+	defaultName string
+}
+
+// NewInstances returns the instances with defaultName as the default. The
+// default may be left empty when there is a single instance. Names are
+// lowercased, as the config file keys they come from are, so they match
+// regardless of case.
+func NewInstances(defaultName string, instances ...Instance) (*Instances, error) {
+	if len(instances) == 0 {
+		return nil, fmt.Errorf("at least one Linkwarden instance is required")
+	}
+
+	byName := make(map[string]Instance, len(instances))
+	for _, instance := range instances {
+		instance.Name = strings.ToLower(instance.Name)
+		if instance.Client == nil {
+			return nil, fmt.Errorf("instance %s: linkwarden client is required", instance.Name)
+		}
+		if _, exists := byName[instance.Name]; exists {
+			return nil, fmt.Errorf("instance %s is configured twice", instance.Name)
+		}
+		byName[instance.Name] = instance
+	}
+
+	defaultName = strings.ToLower(defaultName)
+	if defaultName == "" && len(instances) == 1 {
+		defaultName = strings.ToLower(instances[0].Name)
+	}
+
+	i := &Instances{byName: byName, defaultName: defaultName}
+	if defaultName == "" {
+		return nil, fmt.Errorf("a default instance is required, choose one of: %s",
+			strings.Join(i.Names(), ", "))
+	}
+	if _, exists := byName[defaultName]; !exists {
+		return nil, fmt.Errorf("default instance %q is not one of: %s",
+			defaultName, strings.Join(i.Names(), ", "))
+	}
+	return i, nil
+}
+
+// Names returns the sorted instance names
+func (i *Instances) Names() []string {
+	names := make([]string, 0, len(i.byName))
+	for name := range i.byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// List returns the instances sorted by name
+func (i *Instances) List() []Instance {
+	instances := make([]Instance, 0, len(i.byName))
+	for _, name := range i.Names() {
+		instances = append(instances, i.byName[name])
+	}
+	return instances
+}
+
+// Default returns the client of the default instance
+func (i *Instances) Default() *linkwarden.ClientWithResponses {
+	return i.byName[i.defaultName].Client
+}
+
+// multiple reports whether calls can choose between instances
+func (i *Instances) multiple() bool {
+	return len(i.byName) > 1
+}
+
+// parameter returns the optional argument selecting the instance of a call
+func (i *Instances) parameter() mcpgo.ToolParameter {
+	// The names are listed rather than enforced as an enum, so that they
+	// match regardless of case
+	return mcpgo.WithString(
+		instanceParameter,
+		mcpgo.Description(fmt.Sprintf(
+			"The Linkwarden instance to use, one of: %s. Defaults to %s.",
+			strings.Join(i.Names(), ", "), i.defaultName)),
+	)
+}
+
+// withClient puts the client of the named instance into the context of a
+// call, which the tools prefer over the default client
+func (i *Instances) withClient(ctx context.Context, value any) (context.Context, error) {
+	name, _ := value.(string)
+	if name == "" {
+		return ctx, nil
+	}
+
+	instance, exists := i.byName[strings.ToLower(name)]
+	if !exists {
+		return nil, fmt.Errorf("unknown instance %s, available instances: %s",
+			name, strings.Join(i.Names(), ", "))
+	}
+	return contextkey.WithClient(ctx, instance.Client), nil
+}
+
+// routing returns the tool option adding the instance argument
+func (i *Instances) routing() mcpgo.ToolOption {
+	return mcpgo.WithContextArgument(i.parameter(), i.withClient)
+}
+
+// instanceSummary describes an instance in list_instances
+type instanceSummary struct {
+	Name    string `json:"name"`
+	BaseURL string `json:"baseUrl"`
+	Default bool   `json:"default"`
+}
+
+// ListInstances returns a tool listing the configured Linkwarden instances
+func ListInstances(
+	obs *observability.Observability,
+	instances *Instances,
+) mcpgo.Tool {
+	handler := func(
+		ctx context.Context,
+		req mcpgo.CallToolRequest,
+		args struct{},
+	) ([]instanceSummary, error) {
+		summaries := make([]instanceSummary, 0, len(instances.byName))
+		for _, instance := range instances.List() {
+			summaries = append(summaries, instanceSummary{
+				Name:    instance.Name,
+				BaseURL: instance.BaseURL,
+				Default: instance.Name == instances.defaultName,
+			})
+		}
+		return summaries, nil
+	}
+
+	return mcpgo.NewTypedTool(
+		"list_instances",
+		"Lists the Linkwarden instances this server can use, such as a work "+
+			"and a personal one. Pass the name as the instance argument of "+
+			"other tools to use an instance other than the default.",
+		handler,
+	)
+}
@@ -0,0 +1,95 @@
+package linkwardenmcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/linkwarden"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/log"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/mcpgo"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/observability"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/toolsets"
+)
+
+// newInstanceStub returns an instance whose Linkwarden stub records the
+// paths it was asked for
+func newInstanceStub(t *testing.T, name string, paths *[]string) Instance {
+	t.Helper()
+
+	stub := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			*paths = append(*paths, name+" "+r.URL.Path)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"response":[]}`))
+		},
+	))
+	t.Cleanup(stub.Close)
+
+	client, err := linkwarden.NewClientWithResponses(stub.URL)
+	require.NoError(t, err)
+
+	return Instance{Name: name, BaseURL: stub.URL, Client: client}
+}
+
+func TestToolsRouteCallsToTheNamedInstance(t *testing.T) {
+	var paths []string
+	instances, err := NewInstances("work",
+		newInstanceStub(t, "work", &paths),
+		newInstanceStub(t, "personal", &paths))
+	require.NoError(t, err)
+
+	logger, err := log.NewSlogger()
+	require.NoError(t, err)
+	obs := observability.New(observability.WithLogging(logger))
+
+	catalogs := NewCatalogs(DefaultCatalogTTL)
+	srv, _, err := NewLinkwardenMcpServer(obs, instances, []string{"tags"}, false, false,
+		toolsets.ToolFilter{}, toolsets.ToolCustomization{}, nil, catalogs)
+	require.NoError(t, err)
+	tools := srv.(*mcpgo.Mark3labsImpl).McpServer.ListTools()
+	require.Contains(t, tools, "list_instances")
+
+	call := func(args map[string]any) *mcp.CallToolResult {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = "get_all_tags"
+		req.Params.Arguments = args
+		result, err := tools["get_all_tags"].Handler(context.Background(), req)
+		require.NoError(t, err)
+		return result
+	}
+
+	assert.False(t, call(map[string]any{}).IsError)
+	assert.False(t, call(map[string]any{"instance": "personal"}).IsError)
+	assert.True(t, call(map[string]any{"instance": "home"}).IsError)
+	assert.Equal(t, []string{"work /api/v1/tags", "personal /api/v1/tags"}, paths)
+
+	// Instance names match regardless of case
+	catalogs.Invalidate()
+	assert.False(t, call(map[string]any{"instance": "Personal"}).IsError)
+	assert.Equal(t, "personal /api/v1/tags", paths[len(paths)-1])
+	assert.Len(t, paths, 3)
+}
+
+func TestNewInstancesRequiresAKnownDefault(t *testing.T) {
+	var paths []string
+	work := newInstanceStub(t, "work", &paths)
+	personal := newInstanceStub(t, "personal", &paths)
+
+	_, err := NewInstances("", work, personal)
+	assert.EqualError(t, err, "a default instance is required, choose one of: personal, work")
+
+	instances, err := NewInstances("", work)
+	require.NoError(t, err)
+	assert.Same(t, work.Client, instances.Default())
+
+	// Config file keys are lowercased, so the default matches any case
+	instances, err = NewInstances("Personal", work, personal)
+	require.NoError(t, err)
+	assert.Same(t, personal.Client, instances.Default())
+}
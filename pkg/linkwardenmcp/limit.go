@@ -0,0 +1,237 @@
+package linkwardenmcp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/linkwarden"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/observability"
+)
+
+// Limit is the budget for one kind of Linkwarden request. Zero values
+// leave the request unlimited.
+type Limit struct {
+	// Rate is the number of requests per second
+	Rate float64 `mapstructure:"rate"`
+
+	// Burst is the number of requests that may be sent at once after a
+	// quiet period, at least 1
+	Burst int `mapstructure:"burst"`
+
+	// MaxConcurrent is the number of requests that may be in flight
+	MaxConcurrent int `mapstructure:"max_concurrent"`
+}
+
+// Limits are the separate budgets of reads and writes of an instance, so
+// a bulk import does not starve searches
+type Limits struct {
+	Read  Limit `mapstructure:"read"`
+	Write Limit `mapstructure:"write"`
+}
+
+// Validate reports a negative budget
+func (l Limits) Validate() error {
+	for kind, limit := range map[string]Limit{"read": l.Read, "write": l.Write} {
+		if limit.Rate < 0 || limit.Burst < 0 || limit.MaxConcurrent < 0 {
+			return fmt.Errorf("%s limits must not be negative", kind)
+		}
+	}
+	return nil
+}
+
+// limiter holds back requests beyond a rate with a token bucket and
+// beyond a number of requests in flight with a semaphore
+type limiter struct {
+	kind  string
+	slots chan struct{}
+
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	// queued is the number of requests waiting for the limiter
+	queued atomic.Int64
+}
+
+// newLimiter returns a limiter enforcing limit
+func newLimiter(kind string, limit Limit) *limiter {
+	l := &limiter{kind: kind, rate: limit.Rate}
+	if limit.MaxConcurrent > 0 {
+		l.slots = make(chan struct{}, limit.MaxConcurrent)
+	}
+	if limit.Rate > 0 {
+		l.burst = math.Max(float64(limit.Burst), 1)
+		l.tokens = l.burst
+		l.last = time.Now()
+	}
+	return l
+}
+
+// reserve takes a token from the bucket and returns how long to wait
+// before using it. Tokens may be taken ahead, which queues the callers.
+func (l *limiter) reserve() time.Duration {
+	if l.rate <= 0 {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// cancel returns a token taken by reserve but not used
+func (l *limiter) cancel() {
+	if l.rate <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens = math.Min(l.burst, l.tokens+1)
+}
+
+// tryAcquire takes a slot and a token without waiting, and reports
+// whether it succeeded
+func (l *limiter) tryAcquire() bool {
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		default:
+			return false
+		}
+	}
+
+	if wait := l.reserve(); wait > 0 {
+		l.cancel()
+		l.release()
+		return false
+	}
+	return true
+}
+
+// acquire waits for a slot and a token. The slot must be released once
+// the request is done.
+func (l *limiter) acquire(ctx context.Context) error {
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if wait := l.reserve(); wait > 0 {
+		if err := sleepContext(ctx, wait); err != nil {
+			l.cancel()
+			l.release()
+			return err
+		}
+	}
+	return nil
+}
+
+// release frees the slot taken by acquire
+func (l *limiter) release() {
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+// limitDoer keeps Linkwarden requests within the limits of the instance
+type limitDoer struct {
+	obs   *observability.Observability
+	read  *limiter
+	write *limiter
+	next  linkwarden.HttpRequestDoer
+}
+
+// NewLimitDoer wraps next with the rate and concurrency limits of reads
+// and writes
+func NewLimitDoer(
+	obs *observability.Observability,
+	limits Limits,
+	next linkwarden.HttpRequestDoer,
+) linkwarden.HttpRequestDoer {
+	return &limitDoer{
+		obs:   obs,
+		read:  newLimiter("read", limits.Read),
+		write: newLimiter("write", limits.Write),
+		next:  next,
+	}
+}
+
+// Do sends the request once the limits allow it
+func (d *limitDoer) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	l := d.write
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		l = d.read
+	}
+
+	if !l.tryAcquire() {
+		depth := l.queued.Add(1)
+		started := time.Now()
+		d.obs.Logger.Infof(ctx, "LINKWARDEN_REQUEST_QUEUED",
+			"method", req.Method,
+			"path", req.URL.Path,
+			"kind", l.kind,
+			"queue_depth", depth)
+
+		err := l.acquire(ctx)
+		l.queued.Add(-1)
+		if err != nil {
+			return nil, fmt.Errorf("gave up waiting for the %s limit: %w", l.kind, err)
+		}
+
+		d.obs.Logger.Debugf(ctx, "LINKWARDEN_REQUEST_DEQUEUED",
+			"method", req.Method,
+			"path", req.URL.Path,
+			"kind", l.kind,
+			"waited", time.Since(started).String(),
+			"queue_depth", l.queued.Load())
+	}
+
+	resp, err := d.next.Do(req)
+	if err != nil {
+		l.release()
+		return nil, err
+	}
+
+	// The request is in flight until its response has been read
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: l.release}
+	return resp, nil
+}
+
+// releasingBody frees the limiter slot of a request when its response
+// body is closed
+type releasingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+// Close closes the body and frees the slot
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
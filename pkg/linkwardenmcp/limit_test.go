@@ -0,0 +1,130 @@
+package linkwardenmcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/linkwarden"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/log"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/observability"
+)
+
+func newTestLimitDoer(limits Limits) *limitDoer {
+	obs := observability.New(observability.WithLogging(log.NewDiscardLogger()))
+	return NewLimitDoer(obs, limits, http.DefaultClient).(*limitDoer)
+}
+
+// sendConcurrently sends n requests at once and returns a channel that
+// is closed once all of them are done
+func sendConcurrently(t *testing.T, doer linkwarden.HttpRequestDoer, method, url string, n int) <-chan struct{} {
+	var wg sync.WaitGroup
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := http.NewRequest(method, url, nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+			resp, err := doer.Do(req)
+			if !assert.NoError(t, err) {
+				return
+			}
+			_ = resp.Body.Close()
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	return done
+}
+
+// newBlockingStub answers every request once release is closed, and
+// signals on the returned channel when a request arrives
+func newBlockingStub(t *testing.T, release <-chan struct{}) (*httptest.Server, <-chan struct{}) {
+	arrived := make(chan struct{}, 16)
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arrived <- struct{}{}
+		<-release
+	}))
+	t.Cleanup(stub.Close)
+	return stub, arrived
+}
+
+func TestLimitDoerCapsConcurrentRequests(t *testing.T) {
+	release := make(chan struct{})
+	stub, arrived := newBlockingStub(t, release)
+
+	doer := newTestLimitDoer(Limits{Read: Limit{MaxConcurrent: 2}})
+	done := sendConcurrently(t, doer, http.MethodGet, stub.URL, 6)
+
+	// Two requests reach Linkwarden, the other four queue behind them
+	<-arrived
+	<-arrived
+	assert.Eventually(t, func() bool { return doer.read.queued.Load() == 4 },
+		time.Second, time.Millisecond)
+	assert.Empty(t, arrived)
+
+	close(release)
+	<-done
+	assert.Len(t, arrived, 4)
+}
+
+func TestLimitDoerRateLimitsReadsAndWritesSeparately(t *testing.T) {
+	doer := newTestLimitDoer(Limits{Write: Limit{Rate: 20, Burst: 1}})
+
+	// The first write uses the burst, each next one waits 50ms longer
+	assert.Zero(t, doer.write.reserve())
+	previous := time.Duration(0)
+	for range 3 {
+		wait := doer.write.reserve()
+		assert.Greater(t, wait, previous)
+		assert.LessOrEqual(t, wait, previous+50*time.Millisecond)
+		previous = wait
+	}
+	assert.Greater(t, previous, 100*time.Millisecond)
+
+	// Reads have their own, unlimited budget
+	for range 4 {
+		assert.Zero(t, doer.read.reserve())
+	}
+}
+
+func TestLimitDoerGivesUpWhenCancelled(t *testing.T) {
+	release := make(chan struct{})
+	stub, arrived := newBlockingStub(t, release)
+	defer close(release)
+
+	doer := newTestLimitDoer(Limits{Write: Limit{MaxConcurrent: 1}})
+	go func() {
+		req, _ := http.NewRequest(http.MethodDelete, stub.URL, nil)
+		if resp, err := doer.Do(req); err == nil {
+			_ = resp.Body.Close()
+		}
+	}()
+	<-arrived
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, stub.URL, nil)
+	require.NoError(t, err)
+	_, err = doer.Do(req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "gave up waiting for the write limit")
+}
+
+func TestLimitsValidate(t *testing.T) {
+	assert.NoError(t, Limits{Read: Limit{Rate: 5}}.Validate())
+	assert.EqualError(t, Limits{Write: Limit{MaxConcurrent: -1}}.Validate(),
+		"write limits must not be negative")
+}
@@ -2,121 +2,87 @@ package linkwardenmcp
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/irfansofyana/linkwarden-mcp-server/pkg/linkwarden"
 	"github.com/irfansofyana/linkwarden-mcp-server/pkg/mcpgo"
 	"github.com/irfansofyana/linkwarden-mcp-server/pkg/observability"
 )
 
+// linkSearchArgs are the search filters shared by the tools listing links
+type linkSearchArgs struct {
+	PinnedOnly          *bool   `json:"pinnedOnly,omitempty" description:"Whether to return only pinned links."`
+	SearchQueryString   *string `json:"searchQueryString,omitempty" description:"A string to filter search results."`
+	SearchByName        *bool   `json:"searchByName,omitempty" description:"Whether to search by name."`
+	SearchByUrl         *bool   `json:"searchByUrl,omitempty" description:"Whether to search by URL."`
+	SearchByDescription *bool   `json:"searchByDescription,omitempty" description:"Whether to search by description."`
+	SearchByTextContent *bool   `json:"searchByTextContent,omitempty" description:"Whether to search by text content."`
+	SearchByTags        *bool   `json:"searchByTags,omitempty" description:"Whether to search by tags."`
+}
+
+// getAllLinksArgs are the arguments of get_all_links
+type getAllLinksArgs struct {
+	Sort         *int `json:"sort,omitempty" description:"A numeric value to sort the results."`
+	Cursor       *int `json:"cursor,omitempty" description:"A numeric value for pagination."`
+	CollectionId *int `json:"collectionId,omitempty" description:"Filter by collection ID."`
+	TagId        *int `json:"tagId,omitempty" description:"Filter by tag ID."`
+	linkSearchArgs
+}
+
 // GetAllLinks returns a tool for getting all links with filtering
 func GetAllLinks(
 	obs *observability.Observability,
 	client *linkwarden.ClientWithResponses,
+	access *AccessPolicy,
 ) mcpgo.Tool {
-	params := []mcpgo.ToolParameter{
-		mcpgo.WithNumber(
-			"sort",
-			mcpgo.Description("A numeric value to sort the results."),
-		),
-		mcpgo.WithNumber(
-			"cursor",
-			mcpgo.Description("A numeric value for pagination."),
-		),
-		mcpgo.WithNumber(
-			"collectionId",
-			mcpgo.Description("Filter by collection ID."),
-		),
-		mcpgo.WithNumber(
-			"tagId",
-			mcpgo.Description("Filter by tag ID."),
-		),
-		mcpgo.WithBoolean(
-			"pinnedOnly",
-			mcpgo.Description("Whether to return only pinned links."),
-		),
-		mcpgo.WithString(
-			"searchQueryString",
-			mcpgo.Description("A string to filter search results."),
-		),
-		mcpgo.WithBoolean(
-			"searchByName",
-			mcpgo.Description("Whether to search by name."),
-		),
-		mcpgo.WithBoolean(
-			"searchByUrl",
-			mcpgo.Description("Whether to search by URL."),
-		),
-		mcpgo.WithBoolean(
-			"searchByDescription",
-			mcpgo.Description("Whether to search by description."),
-		),
-		mcpgo.WithBoolean(
-			"searchByTextContent",
-			mcpgo.Description("Whether to search by text content."),
-		),
-		mcpgo.WithBoolean(
-			"searchByTags",
-			mcpgo.Description("Whether to search by tags."),
-		),
-	}
-
-	handler := func(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.ToolResult, error) {
-		client, err := getClientFromContextOrDefault(ctx, client)
-		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
-		}
-
-		args := make(map[string]interface{})
-
-		validator := NewValidator(&req)
-		validator.ValidateAndAddOptionalInt(args, "sort")
-		validator.ValidateAndAddOptionalInt(args, "cursor")
-		validator.ValidateAndAddOptionalInt(args, "collectionId")
-		validator.ValidateAndAddOptionalInt(args, "tagId")
-		validator.ValidateAndAddOptionalBool(args, "pinnedOnly")
-		validator.ValidateAndAddOptionalString(args, "searchQueryString")
-		validator.ValidateAndAddOptionalBool(args, "searchByName")
-		validator.ValidateAndAddOptionalBool(args, "searchByUrl")
-		validator.ValidateAndAddOptionalBool(args, "searchByDescription")
-		validator.ValidateAndAddOptionalBool(args, "searchByTextContent")
-		validator.ValidateAndAddOptionalBool(args, "searchByTags")
-
-		if result, err := validator.HandleErrorsIfAny(); result != nil {
-			return result, err
+	handler := func(
+		ctx context.Context,
+		client *linkwarden.ClientWithResponses,
+		req mcpgo.CallToolRequest,
+		args getAllLinksArgs,
+	) (*linkwarden.LinksResponse, error) {
+		if args.CollectionId != nil {
+			if err := access.checkCollection(ctx, client, *args.CollectionId, AccessRead); err != nil {
+				return nil, err
+			}
 		}
 
-		params := &linkwarden.GetApiV1LinksParams{}
-		mappings := []ParameterMapping{
-			{Key: "sort", Target: &params.Sort, Type: "int"},
-			{Key: "cursor", Target: &params.Cursor, Type: "int"},
-			{Key: "collectionId", Target: &params.CollectionId, Type: "int"},
-			{Key: "tagId", Target: &params.TagId, Type: "int"},
-			{Key: "pinnedOnly", Target: &params.PinnedOnly, Type: "bool"},
-			{Key: "searchQueryString", Target: &params.SearchQueryString, Type: "string"},
-			{Key: "searchByName", Target: &params.SearchByName, Type: "bool"},
-			{Key: "searchByUrl", Target: &params.SearchByUrl, Type: "bool"},
-			{Key: "searchByDescription", Target: &params.SearchByDescription, Type: "bool"},
-			{Key: "searchByTextContent", Target: &params.SearchByTextContent, Type: "bool"},
-			{Key: "searchByTags", Target: &params.SearchByTags, Type: "bool"},
+		params := &linkwarden.GetApiV1LinksParams{
+			Sort:                args.Sort,
+			Cursor:              args.Cursor,
+			CollectionId:        args.CollectionId,
+			TagId:               args.TagId,
+			PinnedOnly:          args.PinnedOnly,
+			SearchQueryString:   args.SearchQueryString,
+			SearchByName:        args.SearchByName,
+			SearchByUrl:         args.SearchByUrl,
+			SearchByDescription: args.SearchByDescription,
+			SearchByTextContent: args.SearchByTextContent,
+			SearchByTags:        args.SearchByTags,
 		}
-		SetOptionalParameters(args, mappings)
 
 		resp, err := client.GetApiV1LinksWithResponse(ctx, params)
 		if err != nil {
-			return mcpgo.NewToolResultError("Failed to get links: " + err.Error()), nil
+			return nil, requestFailed(err)
 		}
 
 		if resp.JSON200 != nil {
-			return mcpgo.NewToolResultJSON(resp.JSON200)
+			links, err := access.filterLinks(ctx, client, deref(resp.JSON200.Response))
+			if err != nil {
+				return nil, err
+			}
+			return &linkwarden.LinksResponse{Response: &links}, nil
 		}
 
-		return mcpgo.NewToolResultError("Failed to get links: " + resp.Status()), nil
+		return nil, unexpectedStatus(resp)
 	}
 
-	return mcpgo.NewTool(
+	return newTool(
 		"get_all_links",
 		"Gets all links with optional filtering and pagination.",
-		params,
+		"get links",
+		client,
 		handler,
 	)
 }
@@ -125,296 +91,288 @@ func GetAllLinks(
 func GetLinkById(
 	obs *observability.Observability,
 	client *linkwarden.ClientWithResponses,
+	access *AccessPolicy,
 ) mcpgo.Tool {
-	params := []mcpgo.ToolParameter{
-		mcpgo.WithNumber(
-			"id",
-			mcpgo.Description("The ID of the link to retrieve."),
-		),
+	type getLinkByIdArgs struct {
+		Id int `json:"id" description:"The ID of the link to retrieve." required:"true"`
 	}
 
-	handler := func(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.ToolResult, error) {
-		client, err := getClientFromContextOrDefault(ctx, client)
-		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
-		}
-
-		args := make(map[string]interface{})
-
-		validator := NewValidator(&req)
-		validator.ValidateAndAddRequiredInt(args, "id")
-
-		if result, err := validator.HandleErrorsIfAny(); result != nil {
-			return result, err
-		}
-
-		id := int(args["id"].(int64))
-
-		resp, err := client.GetLinkWithResponse(ctx, id)
+	handler := func(
+		ctx context.Context,
+		client *linkwarden.ClientWithResponses,
+		req mcpgo.CallToolRequest,
+		args getLinkByIdArgs,
+	) (*linkwarden.LinkResponse, error) {
+		resp, err := client.GetLinkWithResponse(ctx, args.Id)
 		if err != nil {
-			return mcpgo.NewToolResultError("Failed to get link: " + err.Error()), nil
+			return nil, requestFailed(err)
 		}
 
 		if resp.JSON200 != nil {
-			return mcpgo.NewToolResultJSON(resp.JSON200)
+			if resp.JSON200.Response != nil {
+				if err := access.checkLinkIn(ctx, client, *resp.JSON200.Response, AccessRead); err != nil {
+					return nil, err
+				}
+			}
+			return resp.JSON200, nil
 		}
 
-		return mcpgo.NewToolResultError("Failed to get link: " + resp.Status()), nil
+		return nil, unexpectedStatus(resp)
 	}
 
-	return mcpgo.NewTool(
+	return newTool(
 		"get_link_by_id",
 		"Gets a link by its ID.",
-		params,
+		"get link",
+		client,
 		handler,
 	)
 }
 
+// createLinkTag is a tag to add to a new link
+type createLinkTag struct {
+	Id   *int   `json:"id,omitempty" description:"The ID of an existing tag."`
+	Name string `json:"name" description:"The name of the tag." required:"true"`
+}
+
+// createLinkArgs are the arguments of create_link
+type createLinkArgs struct {
+	Name           string          `json:"name" description:"The name of the link." required:"true"`
+	Url            string          `json:"url" description:"The URL of the link." required:"true"`
+	Description    *string         `json:"description,omitempty" description:"The description of the link."`
+	Type           *string         `json:"type,omitempty" description:"The type of the link (url, image, pdf)."`
+	CollectionId   *int            `json:"collectionId,omitempty" description:"The ID of the collection to add the link to."`
+	CollectionName *string         `json:"collectionName,omitempty" description:"The name of the collection to add the link to."`
+	Tags           []createLinkTag `json:"tags,omitempty" description:"List of tags to add to the link. Each tag has a 'name' and, for existing tags, an 'id'."`
+}
+
 // CreateLink returns a tool for creating a new link
 func CreateLink(
 	obs *observability.Observability,
 	client *linkwarden.ClientWithResponses,
+	access *AccessPolicy,
+	catalogs *Catalogs,
 ) mcpgo.Tool {
-	params := []mcpgo.ToolParameter{
-		mcpgo.WithString(
-			"name",
-			mcpgo.Description("The name of the link."),
-		),
-		mcpgo.WithString(
-			"url",
-			mcpgo.Description("The URL of the link."),
-		),
-		mcpgo.WithString(
-			"description",
-			mcpgo.Description("The description of the link."),
-		),
-		mcpgo.WithString(
-			"type",
-			mcpgo.Description("The type of the link (url, image, pdf)."),
-		),
-		mcpgo.WithNumber(
-			"collectionId",
-			mcpgo.Description("The ID of the collection to add the link to."),
-		),
-		mcpgo.WithString(
-			"collectionName",
-			mcpgo.Description("The name of the collection to add the link to."),
-		),
-		mcpgo.WithArray(
-			"tags",
-			mcpgo.Description("List of tags to add to the link. Each tag should have 'id' and 'name' fields."),
-		),
-	}
-
-	handler := func(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.ToolResult, error) {
-		client, err := getClientFromContextOrDefault(ctx, client)
-		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
+	handler := func(
+		ctx context.Context,
+		client *linkwarden.ClientWithResponses,
+		req mcpgo.CallToolRequest,
+		args createLinkArgs,
+	) (*linkwarden.LinkResponse, error) {
+		if err := checkLinkDestination(ctx, client, access, args); err != nil {
+			return nil, err
 		}
 
-		args := make(map[string]interface{})
-
-		validator := NewValidator(&req)
-		validator.ValidateAndAddRequiredString(args, "name")
-		validator.ValidateAndAddRequiredString(args, "url")
-		validator.ValidateAndAddOptionalString(args, "description")
-		validator.ValidateAndAddOptionalString(args, "type")
-		validator.ValidateAndAddOptionalInt(args, "collectionId")
-		validator.ValidateAndAddOptionalString(args, "collectionName")
-		validator.ValidateAndAddOptionalArray(args, "tags")
-
-		if result, err := validator.HandleErrorsIfAny(); result != nil {
-			return result, err
+		body := &linkwarden.CreateLinkJSONRequestBody{
+			Name: &args.Name,
+			Url:  &args.Url,
 		}
 
-		body := &linkwarden.CreateLinkJSONRequestBody{}
-
-		// Set required fields
-		name := args["name"].(string)
-		url := args["url"].(string)
-		body.Name = &name
-		body.Url = &url
-
 		// Set optional fields
-		if description, ok := args["description"].(string); ok {
-			body.Description = &description
+		if args.Description != nil {
+			body.Description = args.Description
 		} else {
-			body.Description = &name
+			body.Description = &args.Name
 		}
 
-		if typeVal, ok := args["type"].(string); ok {
-			typeEnum := linkwarden.CreateLinkJSONBodyType(typeVal)
-			body.Type = &typeEnum
-		} else {
-			typeEnum := linkwarden.CreateLinkJSONBodyType("url")
-			body.Type = &typeEnum
+		typeEnum := linkwarden.CreateLinkJSONBodyType("url")
+		if args.Type != nil {
+			typeEnum = linkwarden.CreateLinkJSONBodyType(*args.Type)
 		}
+		body.Type = &typeEnum
 
 		// Set collection (only if provided)
-		if collectionId, ok := args["collectionId"].(int64); ok {
-			id := int(collectionId)
+		if args.CollectionId != nil {
 			body.Collection = &struct {
 				Id   *int    `json:"id,omitempty"`
 				Name *string `json:"name,omitempty"`
 			}{
-				Id: &id,
+				Id: args.CollectionId,
 			}
-		} else if collectionName, ok := args["collectionName"].(string); ok {
+		} else if args.CollectionName != nil {
 			body.Collection = &struct {
 				Id   *int    `json:"id,omitempty"`
 				Name *string `json:"name,omitempty"`
 			}{
-				Name: &collectionName,
+				Name: args.CollectionName,
 			}
 		}
 
 		// Set tags (only if provided)
-		if tags, ok := args["tags"].([]interface{}); ok {
+		if args.Tags != nil {
 			tagStructs := make([]struct {
 				Id   *int    `json:"id,omitempty"`
 				Name *string `json:"name,omitempty"`
-			}, len(tags))
-
-			for i, tag := range tags {
-				if tagMap, ok := tag.(map[string]interface{}); ok {
-					if idVal, ok := tagMap["id"].(int64); ok {
-						id := int(idVal)
-						tagStructs[i].Id = &id
-					}
-					if nameVal, ok := tagMap["name"].(string); ok {
-						tagStructs[i].Name = &nameVal
-					}
-				}
+			}, len(args.Tags))
+
+			for i := range args.Tags {
+				tagStructs[i].Id = args.Tags[i].Id
+				tagStructs[i].Name = &args.Tags[i].Name
 			}
 			body.Tags = &tagStructs
 		}
 
 		resp, err := client.CreateLinkWithResponse(ctx, *body)
 		if err != nil {
-			return mcpgo.NewToolResultError("Failed to create link: " + err.Error()), nil
+			return nil, requestFailed(err)
 		}
 
 		if resp.JSON200 != nil {
-			return mcpgo.NewToolResultJSON(resp.JSON200)
+			// Linkwarden creates the collection and tags given by name
+			// when they do not exist yet
+			catalogs.catalogFor(client).Invalidate()
+			return resp.JSON200, nil
 		}
 
-		return mcpgo.NewToolResultError("Failed to create link: " + resp.Status()), nil
+		return nil, unexpectedStatus(resp)
 	}
 
-	return mcpgo.NewTool(
+	return newTool(
 		"create_link",
 		"Creates a new link.",
-		params,
+		"create link",
+		client,
 		handler,
 	)
 }
 
+// checkLinkDestination checks that the collection a new link is saved to
+// is writable. Without a collection Linkwarden uses Unorganized.
+func checkLinkDestination(
+	ctx context.Context,
+	client *linkwarden.ClientWithResponses,
+	access *AccessPolicy,
+	args createLinkArgs,
+) error {
+	switch {
+	case args.CollectionId != nil:
+		return access.checkCollection(ctx, client, *args.CollectionId, AccessWrite)
+	case args.CollectionName != nil:
+		return access.checkCollectionNamed(ctx, client, *args.CollectionName, AccessWrite)
+	default:
+		return access.checkCollectionNamed(ctx, client, unorganizedCollectionName, AccessWrite)
+	}
+}
+
 // DeleteLinkById returns a tool for deleting a link by ID
 func DeleteLinkById(
 	obs *observability.Observability,
 	client *linkwarden.ClientWithResponses,
+	access *AccessPolicy,
+	catalogs *Catalogs,
 ) mcpgo.Tool {
-	params := []mcpgo.ToolParameter{
-		mcpgo.WithNumber(
-			"id",
-			mcpgo.Description("The ID of the link to delete."),
-		),
+	type deleteLinkByIdArgs struct {
+		Id int `json:"id" description:"The ID of the link to delete." required:"true"`
 	}
 
-	handler := func(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.ToolResult, error) {
-		client, err := getClientFromContextOrDefault(ctx, client)
-		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
+	handler := func(
+		ctx context.Context,
+		client *linkwarden.ClientWithResponses,
+		req mcpgo.CallToolRequest,
+		args deleteLinkByIdArgs,
+	) (string, error) {
+		if err := access.checkLink(ctx, client, args.Id, AccessWrite); err != nil {
+			return "", err
 		}
 
-		args := make(map[string]interface{})
-
-		validator := NewValidator(&req)
-		validator.ValidateAndAddRequiredInt(args, "id")
-
-		if result, err := validator.HandleErrorsIfAny(); result != nil {
-			return result, err
-		}
-
-		id := int(args["id"].(int64))
-
-		resp, err := client.DeleteLinkWithResponse(ctx, id)
+		resp, err := client.DeleteLinkWithResponse(ctx, args.Id)
 		if err != nil {
-			return mcpgo.NewToolResultError("Failed to delete link: " + err.Error()), nil
+			return "", requestFailed(err)
 		}
 
 		if resp.StatusCode() == 200 {
-			return mcpgo.NewToolResultText("Link deleted successfully"), nil
+			// The link took its tag usage along
+			catalogs.catalogFor(client).InvalidateTags()
+			return "Link deleted successfully", nil
 		}
 
-		return mcpgo.NewToolResultError("Failed to delete link: " + resp.Status()), nil
+		return "", unexpectedStatus(resp)
 	}
 
-	return mcpgo.NewTool(
+	return newTool(
 		"delete_link_by_id",
 		"Deletes a link by its ID.",
-		params,
+		"delete link",
+		client,
 		handler,
+		mcpgo.WithDestructiveHint(true),
+		mcpgo.WithIdempotentHint(true),
 	)
 }
 
+// deleteLinksBatchSize is the number of links deleted per API request
+const deleteLinksBatchSize = 50
+
+// deleteLinksArgs are the arguments of delete_links
+type deleteLinksArgs struct {
+	LinkIds []int `json:"linkIds" description:"List of link IDs to delete." required:"true"`
+}
+
 // DeleteLinks returns a tool for deleting multiple links
 func DeleteLinks(
 	obs *observability.Observability,
 	client *linkwarden.ClientWithResponses,
+	access *AccessPolicy,
+	catalogs *Catalogs,
 ) mcpgo.Tool {
-	params := []mcpgo.ToolParameter{
-		mcpgo.WithArray(
-			"linkIds",
-			mcpgo.Description("List of link IDs to delete."),
-		),
-	}
-
-	handler := func(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.ToolResult, error) {
-		client, err := getClientFromContextOrDefault(ctx, client)
-		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
+	handler := func(
+		ctx context.Context,
+		client *linkwarden.ClientWithResponses,
+		req mcpgo.CallToolRequest,
+		args deleteLinksArgs,
+	) (string, error) {
+		linkIds := args.LinkIds
+		for _, id := range linkIds {
+			if err := access.checkLink(ctx, client, id, AccessWrite); err != nil {
+				return "", err
+			}
 		}
 
-		args := make(map[string]interface{})
-
-		validator := NewValidator(&req)
-		validator.ValidateAndAddRequiredArray(args, "linkIds")
+		// Delete in batches so progress can be reported for large requests
+		total := float64(len(linkIds))
+		req.ReportProgress(ctx, 0, total, fmt.Sprintf("Deleting %d links", len(linkIds)))
 
-		if result, err := validator.HandleErrorsIfAny(); result != nil {
-			return result, err
-		}
+		for start := 0; start < len(linkIds); start += deleteLinksBatchSize {
+			end := min(start+deleteLinksBatchSize, len(linkIds))
+			batch := linkIds[start:end]
 
-		linkIdsInterface := args["linkIds"].([]interface{})
-		linkIds := make([]int, len(linkIdsInterface))
+			body := linkwarden.DeleteLinksJSONRequestBody{
+				LinkIds: &batch,
+			}
 
-		for i, id := range linkIdsInterface {
-			if idVal, ok := id.(int64); ok {
-				linkIds[i] = int(idVal)
+			resp, err := client.DeleteLinksWithResponse(ctx, body)
+			if err != nil {
+				return "", fmt.Errorf("%w (deleted %d of %d)",
+					requestFailed(err), start, len(linkIds))
 			}
-		}
 
-		body := linkwarden.DeleteLinksJSONRequestBody{
-			LinkIds: &linkIds,
-		}
+			if resp.StatusCode() != 200 {
+				return "", fmt.Errorf("%w (deleted %d of %d)",
+					unexpectedStatus(resp), start, len(linkIds))
+			}
 
-		resp, err := client.DeleteLinksWithResponse(ctx, body)
-		if err != nil {
-			return mcpgo.NewToolResultError("Failed to delete links: " + err.Error()), nil
-		}
+			// The deleted links took their tag usage along, even if a
+			// later batch fails
+			catalogs.catalogFor(client).InvalidateTags()
 
-		if resp.StatusCode() == 200 {
-			return mcpgo.NewToolResultText("Links deleted successfully"), nil
+			req.ReportProgress(ctx, float64(end), total,
+				fmt.Sprintf("Deleted %d of %d links", end, len(linkIds)))
 		}
 
-		return mcpgo.NewToolResultError("Failed to delete links: " + resp.Status()), nil
+		return "Links deleted successfully", nil
 	}
 
-	return mcpgo.NewTool(
+	return newTool(
 		"delete_links",
 		"Deletes multiple links by their IDs.",
-		params,
+		"delete links",
+		client,
 		handler,
+		// Large deletions run in many batches
+		mcpgo.WithTimeout(5*time.Minute),
+		mcpgo.WithConfirmation(describeLinksDeletion()),
+		mcpgo.WithDestructiveHint(true),
+		mcpgo.WithIdempotentHint(true),
 	)
 }
 
@@ -422,47 +380,39 @@ func DeleteLinks(
 func ArchiveLink(
 	obs *observability.Observability,
 	client *linkwarden.ClientWithResponses,
+	access *AccessPolicy,
 ) mcpgo.Tool {
-	params := []mcpgo.ToolParameter{
-		mcpgo.WithNumber(
-			"id",
-			mcpgo.Description("The ID of the link to archive."),
-		),
+	type archiveLinkArgs struct {
+		Id int `json:"id" description:"The ID of the link to archive." required:"true"`
 	}
 
-	handler := func(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.ToolResult, error) {
-		client, err := getClientFromContextOrDefault(ctx, client)
-		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
-		}
-
-		args := make(map[string]interface{})
-
-		validator := NewValidator(&req)
-		validator.ValidateAndAddRequiredInt(args, "id")
-
-		if result, err := validator.HandleErrorsIfAny(); result != nil {
-			return result, err
+	handler := func(
+		ctx context.Context,
+		client *linkwarden.ClientWithResponses,
+		req mcpgo.CallToolRequest,
+		args archiveLinkArgs,
+	) (string, error) {
+		if err := access.checkLink(ctx, client, args.Id, AccessWrite); err != nil {
+			return "", err
 		}
 
-		id := int(args["id"].(int64))
-
-		resp, err := client.ArchiveLinkWithResponse(ctx, id)
+		resp, err := client.ArchiveLinkWithResponse(ctx, args.Id)
 		if err != nil {
-			return mcpgo.NewToolResultError("Failed to archive link: " + err.Error()), nil
+			return "", requestFailed(err)
 		}
 
 		if resp.StatusCode() == 200 {
-			return mcpgo.NewToolResultText("Link archived successfully"), nil
+			return "Link archived successfully", nil
 		}
 
-		return mcpgo.NewToolResultError("Failed to archive link: " + resp.Status()), nil
+		return "", unexpectedStatus(resp)
 	}
 
-	return mcpgo.NewTool(
+	return newTool(
 		"archive_link",
 		"Archives a link by its ID.",
-		params,
+		"archive link",
+		client,
 		handler,
 	)
 }
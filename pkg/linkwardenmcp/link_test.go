@@ -0,0 +1,87 @@
+package linkwardenmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/log"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/mcpgo"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/observability"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/toolsets"
+)
+
+// testSession is a client session collecting the notifications it is sent
+type testSession struct {
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *testSession) Initialize()       {}
+func (s *testSession) Initialized() bool { return true }
+func (s *testSession) SessionID() string { return "test" }
+func (s *testSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+
+func TestDeleteLinksReportsProgressPerBatch(t *testing.T) {
+	var batches []int
+	client := newStubClient(t, map[string]http.HandlerFunc{
+		"DELETE /api/v1/links": func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			var request struct {
+				LinkIds []int `json:"linkIds"`
+			}
+			_ = json.Unmarshal(body, &request)
+			batches = append(batches, len(request.LinkIds))
+			_, _ = w.Write([]byte(`{"response":{}}`))
+		},
+	})
+	instances, err := NewInstances("", Instance{Name: "default", Client: client})
+	require.NoError(t, err)
+
+	obs := observability.New(observability.WithLogging(log.NewDiscardLogger()))
+	srv, _, err := NewLinkwardenMcpServer(obs, instances, []string{"link"}, false, false,
+		toolsets.ToolFilter{}, toolsets.ToolCustomization{}, nil, nil)
+	require.NoError(t, err)
+	mcpServer := srv.(*mcpgo.Mark3labsImpl).McpServer
+
+	ids := make([]string, 120)
+	for i := range ids {
+		ids[i] = fmt.Sprint(i + 1)
+	}
+
+	session := &testSession{notifications: make(chan mcp.JSONRPCNotification, 10)}
+	ctx := mcpServer.WithContext(context.Background(), session)
+	mcpServer.HandleMessage(ctx, json.RawMessage(fmt.Sprintf(
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"delete_links",`+
+			`"arguments":{"linkIds":[%s]},"_meta":{"progressToken":"p1"}}}`,
+		strings.Join(ids, ","))))
+
+	assert.Equal(t, []int{50, 50, 20}, batches)
+
+	var progress []float64
+	var messages []string
+	for len(session.notifications) > 0 {
+		notification := <-session.notifications
+		if notification.Method != "notifications/progress" {
+			continue
+		}
+		progress = append(progress, notification.Params.AdditionalFields["progress"].(float64))
+		messages = append(messages, notification.Params.AdditionalFields["message"].(string))
+	}
+	assert.Equal(t, []float64{0, 50, 100, 120}, progress)
+	assert.Equal(t, []string{
+		"Deleting 120 links",
+		"Deleted 50 of 120 links",
+		"Deleted 100 of 120 links",
+		"Deleted 120 of 120 links",
+	}, messages)
+}
@@ -0,0 +1,566 @@
+package linkwardenmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/linkwarden"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/mcpgo"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/observability"
+)
+
+const (
+	// unorganizedCollectionName is the collection Linkwarden saves links
+	// to when no collection is given
+	unorganizedCollectionName = "Unorganized"
+
+	// maxPromptLinkPages bounds how many pages of links a prompt embeds
+	maxPromptLinkPages = 5
+
+	// defaultDigestDays is the period covered by the reading digest
+	defaultDigestDays = 7
+)
+
+// NewPrompts returns all Linkwarden prompts
+func NewPrompts(
+	obs *observability.Observability,
+	client *linkwarden.ClientWithResponses,
+	access *AccessPolicy,
+	catalogs *Catalogs,
+) []mcpgo.Prompt {
+	return []mcpgo.Prompt{
+		TriageUnorganizedLinks(obs, client, access, catalogs),
+		SummarizeCollection(obs, client, access, catalogs),
+		WeeklyReadingDigest(obs, client, access),
+		CleanDuplicateTags(obs, client, access, catalogs),
+	}
+}
+
+// TriageUnorganizedLinks returns a prompt for sorting unorganized links
+// into collections and tags
+func TriageUnorganizedLinks(
+	obs *observability.Observability,
+	client *linkwarden.ClientWithResponses,
+	access *AccessPolicy,
+	catalogs *Catalogs,
+) mcpgo.Prompt {
+	args := []mcpgo.PromptArgument{
+		mcpgo.WithArgument(
+			"collection",
+			mcpgo.ArgumentDescription("The collection holding unsorted links. Defaults to Unorganized."),
+			mcpgo.CompleteWith(completeCollectionNames(client, access, catalogs)),
+		),
+	}
+
+	handler := func(ctx context.Context, req mcpgo.GetPromptRequest) (*mcpgo.PromptResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+
+		name := req.Arguments["collection"]
+		if name == "" {
+			name = unorganizedCollectionName
+		}
+
+		collections, err := catalogs.catalogFor(client).Collections(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		collections, err = access.filterCollections(ctx, client, collections)
+		if err != nil {
+			return nil, err
+		}
+
+		inbox, err := findCollectionByName(collections, name)
+		if err != nil {
+			return nil, err
+		}
+
+		links, truncated, err := fetchLinks(ctx, client, &linkwarden.GetApiV1LinksParams{
+			CollectionId: inbox.Id,
+		}, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		links, err = access.filterLinks(ctx, client, links)
+		if err != nil {
+			return nil, err
+		}
+
+		// Tags carry link counts from every collection, so they are left
+		// out when the access policy hides some collections
+		existingTags := "None available under the configured access policy."
+		if access.checkEverywhere("listing all tags", AccessRead) == nil {
+			tags, err := catalogs.catalogFor(client).Tags(ctx)
+			if err != nil {
+				return nil, err
+			}
+			existingTags = toPromptJSON(toPromptTags(tags))
+		}
+
+		text := fmt.Sprintf(`Help me triage the %d links in my "%s" collection.
+
+For each link, suggest the best existing collection to move it to and
+up to three tags, preferring existing tags over new ones. Group links
+that clearly belong together and point out links that look like
+duplicates or are no longer worth keeping. Finish with a short summary
+of any new collections or tags you would create.
+
+Links to triage:
+%s%s
+
+Existing collections:
+%s
+
+Existing tags:
+%s`,
+			len(links), name,
+			toPromptJSON(toPromptLinks(links)),
+			truncationNote(links, truncated),
+			toPromptJSON(toPromptCollections(collections)),
+			existingTags)
+
+		return mcpgo.NewPromptResult(
+			fmt.Sprintf("Triage links in %s", name),
+			mcpgo.NewUserMessage(text),
+		), nil
+	}
+
+	return mcpgo.NewPrompt(
+		"triage_unorganized_links",
+		"Suggests collections and tags for links that have not been organized yet.",
+		args,
+		logPromptErrors(obs, handler),
+	)
+}
+
+// SummarizeCollection returns a prompt for summarizing a collection
+func SummarizeCollection(
+	obs *observability.Observability,
+	client *linkwarden.ClientWithResponses,
+	access *AccessPolicy,
+	catalogs *Catalogs,
+) mcpgo.Prompt {
+	args := []mcpgo.PromptArgument{
+		mcpgo.WithArgument(
+			"name",
+			mcpgo.ArgumentDescription("The name of the collection to summarize."),
+			mcpgo.RequiredArgument(),
+			mcpgo.CompleteWith(completeCollectionNames(client, access, catalogs)),
+		),
+	}
+
+	handler := func(ctx context.Context, req mcpgo.GetPromptRequest) (*mcpgo.PromptResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+
+		name := req.Arguments["name"]
+		if name == "" {
+			return nil, fmt.Errorf("missing required argument: name")
+		}
+
+		collections, err := catalogs.catalogFor(client).Collections(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		collections, err = access.filterCollections(ctx, client, collections)
+		if err != nil {
+			return nil, err
+		}
+
+		collection, err := findCollectionByName(collections, name)
+		if err != nil {
+			return nil, err
+		}
+
+		links, truncated, err := fetchLinks(ctx, client, &linkwarden.GetApiV1LinksParams{
+			CollectionId: collection.Id,
+		}, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		links, err = access.filterLinks(ctx, client, links)
+		if err != nil {
+			return nil, err
+		}
+
+		text := fmt.Sprintf(`Summarize my "%s" collection.
+
+Describe what the collection is about in a few sentences, list its main
+themes with the most relevant links for each, and call out anything that
+seems out of place in this collection.
+
+Collection:
+%s
+
+Links (%d):
+%s%s`,
+			deref(collection.Name),
+			toPromptJSON(toPromptCollections([]linkwarden.Collection{*collection})[0]),
+			len(links),
+			toPromptJSON(toPromptLinks(links)),
+			truncationNote(links, truncated))
+
+		return mcpgo.NewPromptResult(
+			fmt.Sprintf("Summary of %s", deref(collection.Name)),
+			mcpgo.NewUserMessage(text),
+		), nil
+	}
+
+	return mcpgo.NewPrompt(
+		"summarize_collection",
+		"Summarizes the themes and highlights of a collection.",
+		args,
+		logPromptErrors(obs, handler),
+	)
+}
+
+// WeeklyReadingDigest returns a prompt for a digest of recently saved links
+func WeeklyReadingDigest(
+	obs *observability.Observability,
+	client *linkwarden.ClientWithResponses,
+	access *AccessPolicy,
+) mcpgo.Prompt {
+	args := []mcpgo.PromptArgument{
+		mcpgo.WithArgument(
+			"days",
+			mcpgo.ArgumentDescription("How many days back the digest covers. Defaults to 7."),
+		),
+	}
+
+	handler := func(ctx context.Context, req mcpgo.GetPromptRequest) (*mcpgo.PromptResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+
+		days := defaultDigestDays
+		if value := req.Arguments["days"]; value != "" {
+			days, err = strconv.Atoi(value)
+			if err != nil || days <= 0 {
+				return nil, fmt.Errorf("invalid argument days: %q", value)
+			}
+		}
+
+		since := time.Now().AddDate(0, 0, -days)
+
+		// Links are sorted newest first, so stop at the first older link
+		sortNewestFirst := 0
+		links, truncated, err := fetchLinks(ctx, client, &linkwarden.GetApiV1LinksParams{
+			Sort: &sortNewestFirst,
+		}, func(link linkwarden.Link) bool {
+			return link.CreatedAt != nil && link.CreatedAt.Before(since)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		links, err = access.filterLinks(ctx, client, links)
+		if err != nil {
+			return nil, err
+		}
+
+		text := fmt.Sprintf(`Write my reading digest for the last %d days.
+
+Group the links I saved by topic, give each group a one-line theme and a
+short note per link on why it is worth reading. Highlight the three links
+I should read first and suggest anything that could be archived or
+deleted.
+
+Links saved since %s (%d):
+%s%s`,
+			days, since.Format("2006-01-02"), len(links),
+			toPromptJSON(toPromptLinks(links)),
+			truncationNote(links, truncated))
+
+		return mcpgo.NewPromptResult(
+			fmt.Sprintf("Reading digest for the last %d days", days),
+			mcpgo.NewUserMessage(text),
+		), nil
+	}
+
+	return mcpgo.NewPrompt(
+		"weekly_reading_digest",
+		"Builds a digest of the links saved recently.",
+		args,
+		logPromptErrors(obs, handler),
+	)
+}
+
+// CleanDuplicateTags returns a prompt for finding and merging duplicate tags
+func CleanDuplicateTags(
+	obs *observability.Observability,
+	client *linkwarden.ClientWithResponses,
+	access *AccessPolicy,
+	catalogs *Catalogs,
+) mcpgo.Prompt {
+	args := []mcpgo.PromptArgument{}
+
+	handler := func(ctx context.Context, req mcpgo.GetPromptRequest) (*mcpgo.PromptResult, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := access.checkEverywhere("listing all tags", AccessRead); err != nil {
+			return nil, err
+		}
+
+		tags, err := catalogs.catalogFor(client).Tags(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		text := fmt.Sprintf(`Help me clean up duplicate tags.
+
+Below are groups of tags whose names only differ in case, spacing or
+punctuation, followed by all of my tags. Look for further duplicates
+such as plurals, abbreviations and synonyms. For each set of duplicates
+recommend which tag to keep, preferring the one used by more links, and
+list the tags to remove with delete_tag_by_id. Do not delete anything
+until I confirm.
+
+Likely duplicates:
+%s
+
+All tags:
+%s`,
+			toPromptJSON(findDuplicateTags(tags)),
+			toPromptJSON(toPromptTags(tags)))
+
+		return mcpgo.NewPromptResult(
+			"Find and clean duplicate tags",
+			mcpgo.NewUserMessage(text),
+		), nil
+	}
+
+	return mcpgo.NewPrompt(
+		"clean_duplicate_tags",
+		"Finds tags that look like duplicates and suggests which ones to remove.",
+		args,
+		logPromptErrors(obs, handler),
+	)
+}
+
+// logPromptErrors wraps a prompt handler so that its failures are logged
+func logPromptErrors(
+	obs *observability.Observability,
+	handler mcpgo.PromptHandler,
+) mcpgo.PromptHandler {
+	return func(ctx context.Context, req mcpgo.GetPromptRequest) (*mcpgo.PromptResult, error) {
+		result, err := handler(ctx, req)
+		if err != nil {
+			obs.Logger.Warningf(ctx, "PROMPT_FAILED",
+				"prompt", req.Name,
+				"error", err)
+		}
+		return result, err
+	}
+}
+
+// promptLink is the compact form of a link embedded in prompts
+type promptLink struct {
+	Id          int        `json:"id"`
+	Name        string     `json:"name,omitempty"`
+	Url         string     `json:"url,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Collection  string     `json:"collection,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+	CreatedAt   *time.Time `json:"createdAt,omitempty"`
+}
+
+// promptCollection is the compact form of a collection embedded in prompts
+type promptCollection struct {
+	Id          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	ParentId    *int   `json:"parentId,omitempty"`
+	Links       int    `json:"links"`
+}
+
+// promptTag is the compact form of a tag embedded in prompts
+type promptTag struct {
+	Id    int    `json:"id"`
+	Name  string `json:"name"`
+	Links int    `json:"links"`
+}
+
+// deref returns the value of p, or the zero value if p is nil
+func deref[T any](p *T) T {
+	var zero T
+	if p == nil {
+		return zero
+	}
+	return *p
+}
+
+func toPromptLinks(links []linkwarden.Link) []promptLink {
+	result := make([]promptLink, 0, len(links))
+	for _, link := range links {
+		pl := promptLink{
+			Id:          deref(link.Id),
+			Name:        deref(link.Name),
+			Url:         deref(link.Url),
+			Description: deref(link.Description),
+			CreatedAt:   link.CreatedAt,
+		}
+		if link.Collection != nil {
+			pl.Collection = deref(link.Collection.Name)
+		}
+		if link.Tags != nil {
+			for _, tag := range *link.Tags {
+				pl.Tags = append(pl.Tags, deref(tag.Name))
+			}
+		}
+		result = append(result, pl)
+	}
+	return result
+}
+
+func toPromptCollections(collections []linkwarden.Collection) []promptCollection {
+	result := make([]promptCollection, 0, len(collections))
+	for _, collection := range collections {
+		pc := promptCollection{
+			Id:          deref(collection.Id),
+			Name:        deref(collection.Name),
+			Description: deref(collection.Description),
+			ParentId:    collection.ParentId,
+		}
+		if collection.UnderscoreCount != nil {
+			pc.Links = deref(collection.UnderscoreCount.Links)
+		}
+		result = append(result, pc)
+	}
+	return result
+}
+
+func toPromptTags(tags []linkwarden.Tag) []promptTag {
+	result := make([]promptTag, 0, len(tags))
+	for _, tag := range tags {
+		pt := promptTag{
+			Id:   deref(tag.Id),
+			Name: deref(tag.Name),
+		}
+		if tag.UnderscoreCount != nil {
+			pt.Links = deref(tag.UnderscoreCount.Links)
+		}
+		result = append(result, pt)
+	}
+	return result
+}
+
+// toPromptJSON renders data as indented JSON for embedding in a prompt
+func toPromptJSON(data interface{}) string {
+	jsonBytes, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("%v", data)
+	}
+	return string(jsonBytes)
+}
+
+// normalizeTagName reduces a tag name to lowercase letters and digits so
+// that "Machine Learning", "machine-learning" and "machinelearning" match
+func normalizeTagName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// findDuplicateTags groups tags whose normalized names are equal
+func findDuplicateTags(tags []linkwarden.Tag) [][]promptTag {
+	groups := make(map[string][]promptTag)
+	var order []string
+	for _, tag := range toPromptTags(tags) {
+		key := normalizeTagName(tag.Name)
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], tag)
+	}
+
+	duplicates := [][]promptTag{}
+	for _, key := range order {
+		if len(groups[key]) > 1 {
+			duplicates = append(duplicates, groups[key])
+		}
+	}
+	return duplicates
+}
+
+// fetchLinks pages through links matching params, up to maxPromptLinkPages
+// pages. If stop is set, paging ends at the first link it returns true
+// for, and that link is not included. truncated reports whether paging
+// ended at the page limit while more links may remain.
+func fetchLinks(
+	ctx context.Context,
+	client *linkwarden.ClientWithResponses,
+	params *linkwarden.GetApiV1LinksParams,
+	stop func(link linkwarden.Link) bool,
+) (links []linkwarden.Link, truncated bool, err error) {
+	for page := 0; page < maxPromptLinkPages; page++ {
+		resp, err := client.GetApiV1LinksWithResponse(ctx, params)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get links: %w", err)
+		}
+
+		if resp.JSON200 == nil {
+			return nil, false, fmt.Errorf("failed to get links: %s", resp.Status())
+		}
+
+		pageLinks := deref(resp.JSON200.Response)
+		if len(pageLinks) == 0 {
+			return links, false, nil
+		}
+
+		for _, link := range pageLinks {
+			if stop != nil && stop(link) {
+				return links, false, nil
+			}
+			links = append(links, link)
+		}
+
+		// Linkwarden paginates with the ID of the last link as cursor
+		params.Cursor = pageLinks[len(pageLinks)-1].Id
+	}
+
+	return links, true, nil
+}
+
+// truncationNote tells the model that only the first links fetched are
+// embedded, or returns "" if all links are
+func truncationNote(links []linkwarden.Link, truncated bool) string {
+	if !truncated {
+		return ""
+	}
+	return fmt.Sprintf("\n\nShowing the first %d links only; more links were not included.", len(links))
+}
+
+// findCollectionByName returns the collection with the given name,
+// ignoring case
+func findCollectionByName(
+	collections []linkwarden.Collection,
+	name string,
+) (*linkwarden.Collection, error) {
+	for i := range collections {
+		if strings.EqualFold(deref(collections[i].Name), name) {
+			return &collections[i], nil
+		}
+	}
+	return nil, fmt.Errorf("collection %q not found", name)
+}
@@ -0,0 +1,115 @@
+package linkwardenmcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/linkwarden"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/log"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/mcpgo"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/observability"
+)
+
+func TestNormalizeTagName(t *testing.T) {
+	assert.Equal(t, "machinelearning", normalizeTagName("Machine Learning"))
+	assert.Equal(t, "machinelearning", normalizeTagName("machine-learning"))
+	assert.Equal(t, "c99", normalizeTagName("C_99!"))
+	assert.Equal(t, "café", normalizeTagName("Café"))
+	assert.Equal(t, "", normalizeTagName("--"))
+}
+
+func TestFindDuplicateTags(t *testing.T) {
+	tag := func(id int, name string) linkwarden.Tag {
+		return linkwarden.Tag{Id: &id, Name: &name}
+	}
+
+	duplicates := findDuplicateTags([]linkwarden.Tag{
+		tag(1, "Go"),
+		tag(2, "Machine Learning"),
+		tag(3, "go"),
+		tag(4, "rust"),
+		tag(5, "machine-learning"),
+		tag(6, "GO "),
+	})
+
+	assert.Equal(t, [][]promptTag{
+		{{Id: 1, Name: "Go"}, {Id: 3, Name: "go"}, {Id: 6, Name: "GO "}},
+		{{Id: 2, Name: "Machine Learning"}, {Id: 5, Name: "machine-learning"}},
+	}, duplicates)
+
+	assert.Empty(t, findDuplicateTags([]linkwarden.Tag{tag(1, "go"), tag(2, "rust")}))
+}
+
+func TestPromptNotesTruncatedLinks(t *testing.T) {
+	pages := 0
+	client := newStubClient(t, map[string]http.HandlerFunc{
+		"GET /api/v1/collections": respondJSON(`{"response":[{"id":1,"name":"Reading"}]}`),
+		"GET /api/v1/links": func(w http.ResponseWriter, r *http.Request) {
+			// Every page is full, so paging only ends at the page limit
+			pages++
+			_, _ = fmt.Fprintf(w, `{"response":[{"id":%d,"name":"Link %d"}]}`, pages, pages)
+		},
+	})
+
+	obs := observability.New(observability.WithLogging(log.NewDiscardLogger()))
+	handler := SummarizeCollection(obs, client, nil, nil).GetHandler()
+
+	result, err := handler(context.Background(), mcpgo.GetPromptRequest{
+		Name:      "summarize_collection",
+		Arguments: map[string]string{"name": "reading"},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Messages, 1)
+
+	assert.Equal(t, maxPromptLinkPages, pages)
+	assert.Contains(t, result.Messages[0].Text,
+		fmt.Sprintf("Showing the first %d links only", maxPromptLinkPages))
+}
+
+func TestPromptsRespectAccessPolicy(t *testing.T) {
+	tagsRequested := false
+	routes := map[string]http.HandlerFunc{
+		"GET /api/v1/collections": respondJSON(`{"response": [
+			{"id": 1, "name": "Inbox", "parentId": null},
+			{"id": 4, "name": "Private", "parentId": null}
+		]}`),
+		"GET /api/v1/links": respondJSON(`{"response": [{"id": 10, "name": "Unsorted", "collectionId": 1}]}`),
+		"GET /api/v1/tags": func(w http.ResponseWriter, r *http.Request) {
+			tagsRequested = true
+			_, _ = w.Write([]byte(`{"response": [{"id": 7, "name": "private-notes"}]}`))
+		},
+	}
+	obs := observability.New(observability.WithLogging(log.NewDiscardLogger()))
+	access := newInboxPolicy(t)
+
+	// Triage leaves out tags and hidden collections
+	triage := TriageUnorganizedLinks(obs, newStubClient(t, routes), access, nil).GetHandler()
+	result, err := triage(context.Background(), mcpgo.GetPromptRequest{
+		Name:      "triage_unorganized_links",
+		Arguments: map[string]string{"collection": "inbox"},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Messages, 1)
+	assert.Contains(t, result.Messages[0].Text, "Unsorted")
+	assert.NotContains(t, result.Messages[0].Text, "Private")
+	assert.NotContains(t, result.Messages[0].Text, "private-notes")
+	assert.False(t, tagsRequested)
+
+	// Cleaning tags needs them all, so it is denied
+	clean := CleanDuplicateTags(obs, newStubClient(t, routes), access, nil).GetHandler()
+	_, err = clean(context.Background(), mcpgo.GetPromptRequest{Name: "clean_duplicate_tags"})
+	assert.EqualError(t, err,
+		"access denied: listing all tags needs read access to every collection")
+	assert.False(t, tagsRequested)
+
+	// Without a policy every tag is included
+	clean = CleanDuplicateTags(obs, newStubClient(t, routes), nil, nil).GetHandler()
+	result, err = clean(context.Background(), mcpgo.GetPromptRequest{Name: "clean_duplicate_tags"})
+	require.NoError(t, err)
+	assert.Contains(t, result.Messages[0].Text, "private-notes")
+}
@@ -0,0 +1,222 @@
+package linkwardenmcp
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/linkwarden"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/observability"
+)
+
+// RetryPolicy limits how failed Linkwarden requests are retried
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt, 0
+	// disables retrying
+	MaxRetries int
+
+	// BaseDelay is the backoff before the first retry. It doubles with
+	// every retry, and a random part of it is waited.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the backoff. A Retry-After asking for a longer wait
+	// is not honored and the response is returned instead.
+	MaxDelay time.Duration
+
+	// RetryNonIdempotent also retries POST and PATCH requests, which may
+	// then be applied twice
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy rides out short outages, such as a Linkwarden restart
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: 2,
+		BaseDelay:  250 * time.Millisecond,
+		MaxDelay:   10 * time.Second,
+	}
+}
+
+// Validate reports whether the policy has negative retries or delays
+func (p RetryPolicy) Validate() error {
+	if p.MaxRetries < 0 {
+		return errors.New("max retries must not be negative")
+	}
+	if p.BaseDelay < 0 || p.MaxDelay < 0 {
+		return errors.New("retry delays must not be negative")
+	}
+	return nil
+}
+
+// retryDoer retries Linkwarden requests that failed for reasons likely
+// to be transient, such as a refused connection or a 502 while
+// Linkwarden restarts
+type retryDoer struct {
+	obs    *observability.Observability
+	policy RetryPolicy
+	next   linkwarden.HttpRequestDoer
+
+	// sleep waits for d unless ctx is done first
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewRetryDoer wraps next with retries with exponential backoff and jitter
+func NewRetryDoer(
+	obs *observability.Observability,
+	policy RetryPolicy,
+	next linkwarden.HttpRequestDoer,
+) linkwarden.HttpRequestDoer {
+	return &retryDoer{obs: obs, policy: policy, next: next, sleep: sleepContext}
+}
+
+// Do sends the request, retrying it while the policy allows
+func (d *retryDoer) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	resp, err := d.next.Do(req)
+	if !d.canRetry(req) {
+		return resp, err
+	}
+
+	for attempt := 1; attempt <= d.policy.MaxRetries && isTransient(ctx, resp, err); attempt++ {
+		delay, ok := d.delay(attempt, resp)
+		if !ok {
+			break
+		}
+
+		retry := req.Clone(ctx)
+		if req.GetBody != nil {
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				break
+			}
+			retry.Body = body
+		}
+
+		d.logRetry(ctx, req, attempt, delay, resp, err)
+		if sleepErr := d.sleep(ctx, delay); sleepErr != nil {
+			break
+		}
+
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+		resp, err = d.next.Do(retry)
+	}
+
+	return resp, err
+}
+
+// canRetry reports whether the request may be sent again
+func (d *retryDoer) canRetry(req *http.Request) bool {
+	if d.policy.MaxRetries <= 0 {
+		return false
+	}
+
+	// A request whose body was consumed cannot be sent again
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return d.policy.RetryNonIdempotent
+}
+
+// delay returns the wait before the given retry. It reports false when
+// Linkwarden asks for a longer wait than the policy allows.
+func (d *retryDoer) delay(attempt int, resp *http.Response) (time.Duration, bool) {
+	if wait, ok := retryAfter(resp); ok {
+		return wait, wait <= d.policy.MaxDelay
+	}
+
+	backoff := d.policy.BaseDelay << (attempt - 1)
+	if backoff <= 0 || backoff > d.policy.MaxDelay {
+		backoff = d.policy.MaxDelay
+	}
+
+	// Waiting between half and all of the backoff keeps clients that
+	// failed together from retrying together
+	half := backoff / 2
+	return half + rand.N(backoff-half+1), true
+}
+
+// logRetry logs why a request is retried
+func (d *retryDoer) logRetry(
+	ctx context.Context,
+	req *http.Request,
+	attempt int,
+	delay time.Duration,
+	resp *http.Response,
+	err error,
+) {
+	args := []interface{}{
+		"method", req.Method,
+		"path", req.URL.Path,
+		"attempt", attempt,
+		"delay", delay.String(),
+	}
+	if err != nil {
+		args = append(args, "error", err)
+	} else {
+		args = append(args, "status", resp.Status)
+	}
+	d.obs.Logger.Infof(ctx, "LINKWARDEN_REQUEST_RETRY", args...)
+}
+
+// isTransient reports whether a failed request is worth retrying
+func isTransient(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		// The caller gave up, retrying would not help
+		return ctx.Err() == nil && !errors.Is(err, context.Canceled) &&
+			!errors.Is(err, context.DeadlineExceeded)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter returns the wait asked for by the Retry-After header of a
+// 429 or 503 response, given in seconds or as a date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil || (resp.StatusCode != http.StatusTooManyRequests &&
+		resp.StatusCode != http.StatusServiceUnavailable) {
+		return 0, false
+	}
+
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+// sleepContext waits for d unless ctx is done first
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,152 @@
+package linkwardenmcp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/log"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/observability"
+)
+
+// newRetryStub answers with the given statuses in turn, then with 200,
+// and records the bodies it receives
+func newRetryStub(t *testing.T, headers http.Header, statuses ...int) (*httptest.Server, *[]string) {
+	var bodies []string
+	stub := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			if len(bodies) <= len(statuses) {
+				for key, values := range headers {
+					w.Header()[key] = values
+				}
+				w.WriteHeader(statuses[len(bodies)-1])
+			}
+		},
+	))
+	t.Cleanup(stub.Close)
+	return stub, &bodies
+}
+
+// newTestRetryDoer returns a retry doer that records its waits instead
+// of sleeping
+func newTestRetryDoer(policy RetryPolicy) (*retryDoer, *[]time.Duration) {
+	var delays []time.Duration
+	obs := observability.New(observability.WithLogging(log.NewDiscardLogger()))
+	doer := NewRetryDoer(obs, policy, http.DefaultClient).(*retryDoer)
+	doer.sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+	return doer, &delays
+}
+
+func sendWithRetry(t *testing.T, doer *retryDoer, method, url, body string) *http.Response {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	resp, err := doer.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	return resp
+}
+
+func TestRetryDoerRetriesTransientFailures(t *testing.T) {
+	stub, bodies := newRetryStub(t, nil, http.StatusBadGateway, http.StatusGatewayTimeout)
+	doer, delays := newTestRetryDoer(RetryPolicy{
+		MaxRetries: 3,
+		BaseDelay:  100 * time.Millisecond,
+		MaxDelay:   150 * time.Millisecond,
+	})
+
+	resp := sendWithRetry(t, doer, http.MethodPut, stub.URL, "link")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"link", "link", "link"}, *bodies)
+
+	// The backoff doubles up to the limit, and half of it is jitter
+	require.Len(t, *delays, 2)
+	assert.GreaterOrEqual(t, (*delays)[0], 50*time.Millisecond)
+	assert.LessOrEqual(t, (*delays)[0], 100*time.Millisecond)
+	assert.GreaterOrEqual(t, (*delays)[1], 75*time.Millisecond)
+	assert.LessOrEqual(t, (*delays)[1], 150*time.Millisecond)
+}
+
+func TestRetryDoerGivesUpAfterMaxRetries(t *testing.T) {
+	stub, bodies := newRetryStub(t, nil,
+		http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+	doer, _ := newTestRetryDoer(RetryPolicy{MaxRetries: 2, MaxDelay: time.Second})
+
+	resp := sendWithRetry(t, doer, http.MethodGet, stub.URL, "")
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	assert.Len(t, *bodies, 3)
+}
+
+func TestRetryDoerSkipsNonIdempotentRequests(t *testing.T) {
+	stub, bodies := newRetryStub(t, nil, http.StatusBadGateway)
+	doer, _ := newTestRetryDoer(RetryPolicy{MaxRetries: 2, MaxDelay: time.Second})
+
+	resp := sendWithRetry(t, doer, http.MethodPost, stub.URL, "link")
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	assert.Len(t, *bodies, 1)
+
+	doer.policy.RetryNonIdempotent = true
+	stub, bodies = newRetryStub(t, nil, http.StatusBadGateway)
+	resp = sendWithRetry(t, doer, http.MethodPost, stub.URL, "link")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"link", "link"}, *bodies)
+}
+
+func TestRetryDoerSkipsPermanentFailures(t *testing.T) {
+	stub, bodies := newRetryStub(t, nil, http.StatusInternalServerError)
+	doer, _ := newTestRetryDoer(RetryPolicy{MaxRetries: 2, MaxDelay: time.Second})
+
+	resp := sendWithRetry(t, doer, http.MethodGet, stub.URL, "")
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Len(t, *bodies, 1)
+}
+
+func TestRetryDoerHonorsRetryAfter(t *testing.T) {
+	stub, _ := newRetryStub(t, http.Header{"Retry-After": {"3"}}, http.StatusTooManyRequests)
+	doer, delays := newTestRetryDoer(RetryPolicy{MaxRetries: 1, MaxDelay: 5 * time.Second})
+
+	resp := sendWithRetry(t, doer, http.MethodGet, stub.URL, "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []time.Duration{3 * time.Second}, *delays)
+
+	// A longer wait than the policy allows returns the response instead
+	stub, bodies := newRetryStub(t, http.Header{"Retry-After": {"60"}}, http.StatusServiceUnavailable)
+	resp = sendWithRetry(t, doer, http.MethodGet, stub.URL, "")
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Len(t, *bodies, 1)
+}
+
+func TestRetryAfterDate(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Header: http.Header{"Retry-After": {
+			time.Now().Add(time.Minute).UTC().Format(http.TimeFormat),
+		}},
+	}
+	wait, ok := retryAfter(resp)
+	assert.True(t, ok)
+	assert.InDelta(t, time.Minute.Seconds(), wait.Seconds(), 2)
+
+	resp.StatusCode = http.StatusBadGateway
+	_, ok = retryAfter(resp)
+	assert.False(t, ok)
+}
+
+func TestRetryPolicyValidate(t *testing.T) {
+	assert.NoError(t, DefaultRetryPolicy().Validate())
+	assert.EqualError(t, RetryPolicy{MaxRetries: -1}.Validate(),
+		"max retries must not be negative")
+	assert.EqualError(t, RetryPolicy{MaxRetries: 2, MaxDelay: -time.Second}.Validate(),
+		"retry delays must not be negative")
+}
@@ -8,79 +8,65 @@ import (
 	"github.com/irfansofyana/linkwarden-mcp-server/pkg/observability"
 )
 
+// searchLinksArgs are the arguments of search_links
+type searchLinksArgs struct {
+	SearchQueryString *string `json:"searchQueryString,omitempty" description:"A string to filter search results."`
+	Sort              *int    `json:"sort,omitempty" description:"A numeric value to sort the search results."`
+	Cursor            *int    `json:"cursor,omitempty" description:"A numeric value for pagination."`
+	CollectionId      *int    `json:"collectionId,omitempty" description:"Filter by collection ID"`
+	TagId             *int    `json:"tagId,omitempty" description:"Filter by tag ID"`
+}
+
 // SearchLinks returns tools for searching links
 func SearchLinks(
 	obs *observability.Observability,
 	client *linkwarden.ClientWithResponses,
+	access *AccessPolicy,
 ) mcpgo.Tool {
-	params := []mcpgo.ToolParameter{
-		mcpgo.WithString(
-			"searchQueryString",
-			mcpgo.Description("A string to filter search results."),
-		),
-		mcpgo.WithNumber(
-			"sort",
-			mcpgo.Description("A numeric value to sort the search results."),
-		),
-		mcpgo.WithNumber(
-			"cursor",
-			mcpgo.Description("A numeric value for pagination."),
-		),
-		mcpgo.WithNumber(
-			"collectionId",
-			mcpgo.Description("Filter by collection ID"),
-		),
-		mcpgo.WithNumber(
-			"tagId",
-			mcpgo.Description("Filter by tag ID"),
-		),
-	}
-
-	handler := func(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.ToolResult, error) {
-		client, err := getClientFromContextOrDefault(ctx, client)
-		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
-		}
-
-		queryParams := make(map[string]interface{})
-
-		validator := NewValidator(&req)
-		validator.ValidateAndAddOptionalString(queryParams, "searchQueryString")
-		validator.ValidateAndAddOptionalInt(queryParams, "sort")
-		validator.ValidateAndAddOptionalInt(queryParams, "cursor")
-		validator.ValidateAndAddOptionalInt(queryParams, "collectionId")
-		validator.ValidateAndAddOptionalInt(queryParams, "tagId")
-
-		if result, err := validator.HandleErrorsIfAny(); result != nil {
-			return result, err
+	handler := func(
+		ctx context.Context,
+		client *linkwarden.ClientWithResponses,
+		req mcpgo.CallToolRequest,
+		args searchLinksArgs,
+	) (*linkwarden.SearchResponse, error) {
+		if args.CollectionId != nil {
+			if err := access.checkCollection(ctx, client, *args.CollectionId, AccessRead); err != nil {
+				return nil, err
+			}
 		}
 
-		params := &linkwarden.SearchLinksParams{}
-		mappings := []ParameterMapping{
-			{Key: "searchQueryString", Target: &params.SearchQueryString, Type: "string"},
-			{Key: "sort", Target: &params.Sort, Type: "int"},
-			{Key: "cursor", Target: &params.Cursor, Type: "int"},
-			{Key: "collectionId", Target: &params.CollectionId, Type: "int"},
-			{Key: "tagId", Target: &params.TagId, Type: "int"},
+		params := &linkwarden.SearchLinksParams{
+			SearchQueryString: args.SearchQueryString,
+			Sort:              args.Sort,
+			Cursor:            args.Cursor,
+			CollectionId:      args.CollectionId,
+			TagId:             args.TagId,
 		}
-		SetOptionalParameters(queryParams, mappings)
 
 		resp, err := client.SearchLinksWithResponse(ctx, params)
 		if err != nil {
-			return mcpgo.NewToolResultError("Failed to search links: " + err.Error()), nil
+			return nil, requestFailed(err)
 		}
 
 		if resp.JSON200 != nil {
-			return mcpgo.NewToolResultJSON(resp.JSON200)
+			if data := resp.JSON200.Data; data != nil && data.Links != nil {
+				links, err := access.filterLinks(ctx, client, *data.Links)
+				if err != nil {
+					return nil, err
+				}
+				data.Links = &links
+			}
+			return resp.JSON200, nil
 		}
 
-		return mcpgo.NewToolResultError("Failed to search links: " + resp.Status()), nil
+		return nil, unexpectedStatus(resp)
 	}
 
-	return mcpgo.NewTool(
+	return newTool(
 		"search_links",
 		"Searches for links based on some query parameters.",
-		params,
+		"search links",
+		client,
 		handler,
 	)
 }
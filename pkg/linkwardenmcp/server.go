@@ -2,61 +2,82 @@ package linkwardenmcp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/irfansofyana/linkwarden-mcp-server/pkg/contextkey"
 	"github.com/irfansofyana/linkwarden-mcp-server/pkg/linkwarden"
 	"github.com/irfansofyana/linkwarden-mcp-server/pkg/mcpgo"
 	"github.com/irfansofyana/linkwarden-mcp-server/pkg/observability"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/toolsets"
 )
 
 func NewLinkwardenMcpServer(
 	obs *observability.Observability,
-	client *linkwarden.ClientWithResponses,
+	instances *Instances,
 	enabledToolsets []string,
 	readOnly bool,
+	dynamicToolsets bool,
+	toolFilter toolsets.ToolFilter,
+	customization toolsets.ToolCustomization,
+	access *AccessPolicy,
+	catalogs *Catalogs,
 	mcpOpts ...mcpgo.ServerOption,
-) (mcpgo.Server, error) {
+) (mcpgo.Server, *toolsets.ToolsetGroup, error) {
 	if obs == nil {
-		return nil, fmt.Errorf("observability is required")
+		return nil, nil, fmt.Errorf("observability is required")
+	}
+
+	if instances == nil {
+		return nil, nil, fmt.Errorf("linkwarden instances are required")
+	}
+
+	access = access.withCatalogs(catalogs)
+
+	toolsetGroup, err := NewToolSets(obs, instances, enabledToolsets, readOnly, dynamicToolsets, toolFilter, customization, access, catalogs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create toolsets: %w", err)
 	}
 
-	if client == nil {
-		return nil, fmt.Errorf("linkwarden client is required")
+	middlewares := []mcpgo.ToolMiddleware{
+		mcpgo.LoggingMiddleware(obs),
+		mcpgo.TimingMiddleware(obs),
+		mcpgo.ReadOnlyMiddleware(toolsetGroup.IsReadOnly),
+		mcpgo.ErrorNormalizationMiddleware(),
+		mcpgo.RecoveryMiddleware(obs),
 	}
 
 	defaultOpts := []mcpgo.ServerOption{
 		mcpgo.WithLogging(),
 		mcpgo.WithResourceCapabilities(true, true),
 		mcpgo.WithToolCapabilities(true),
-		mcpgo.WithHooks(mcpgo.SetupHooks(obs))}
+		mcpgo.WithPromptCapabilities(false),
+		mcpgo.WithHooks(mcpgo.SetupHooks(obs)),
+		mcpgo.WithMiddleware(middlewares...)}
 
 	// Merge with user-provided options
 	mcpOpts = append(defaultOpts, mcpOpts...)
 
 	server := mcpgo.NewMcpServer("linkwarden-mcp", "0.0.1", mcpOpts...)
 
-	toolsets, err := NewToolSets(obs, client, enabledToolsets, readOnly)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create toolsets: %w", err)
-	}
-	toolsets.RegisterTools(server)
+	toolsetGroup.RegisterTools(server)
+
+	server.AddPrompts(NewPrompts(obs, instances.Default(), access, catalogs)...)
 
-	return server, nil
+	return server, toolsetGroup, nil
 }
 
-// getClientFromContextOrDefault returns either the provided default
-// client or gets one from context.
+// getClientFromContextOrDefault returns the client in the context, set
+// when a call names an instance, or else the provided default client.
 func getClientFromContextOrDefault(
 	ctx context.Context,
 	defaultClient *linkwarden.ClientWithResponses,
 ) (*linkwarden.ClientWithResponses, error) {
-	if defaultClient != nil {
-		return defaultClient, nil
-	}
-
 	clientInterface := contextkey.ClientFromContext(ctx)
 	if clientInterface == nil {
+		if defaultClient != nil {
+			return defaultClient, nil
+		}
 		return nil, fmt.Errorf("no client found in context")
 	}
 
@@ -67,3 +88,70 @@ func getClientFromContextOrDefault(
 
 	return client, nil
 }
+
+// toolHandler handles a tool call with the client of the instance the call
+// is for
+type toolHandler[Args, Result any] func(
+	ctx context.Context,
+	client *linkwarden.ClientWithResponses,
+	req mcpgo.CallToolRequest,
+	args Args) (Result, error)
+
+// newTool creates a typed tool whose handler runs against the client of the
+// call's instance. Failed Linkwarden requests are reported as
+// "Failed to <action>: <cause>".
+func newTool[Args, Result any](
+	name,
+	description,
+	action string,
+	client *linkwarden.ClientWithResponses,
+	handler toolHandler[Args, Result],
+	opts ...mcpgo.ToolOption,
+) mcpgo.Tool {
+	typed := func(ctx context.Context, req mcpgo.CallToolRequest, args Args) (Result, error) {
+		client, err := getClientFromContextOrDefault(ctx, client)
+		if err != nil {
+			var zero Result
+			return zero, err
+		}
+
+		result, err := handler(ctx, client, req, args)
+		var reqErr *requestError
+		if errors.As(err, &reqErr) {
+			err = &actionError{action: action, err: err}
+		}
+		return result, err
+	}
+
+	return mcpgo.NewTypedTool(name, description, typed, opts...)
+}
+
+// requestError is a Linkwarden request that could not be sent or that
+// was answered with an unexpected status
+type requestError struct {
+	err error
+}
+
+func (e *requestError) Error() string { return e.err.Error() }
+func (e *requestError) Unwrap() error { return e.err }
+
+// requestFailed returns the error of a request that could not be sent
+func requestFailed(err error) error {
+	return &requestError{err: err}
+}
+
+// unexpectedStatus returns the error of a request answered with a status
+// the tool does not handle
+func unexpectedStatus(resp interface{ Status() string }) error {
+	return &requestError{err: errors.New(resp.Status())}
+}
+
+// actionError names the tool action a failed request was made for. Its
+// message is shown to the client.
+type actionError struct {
+	action string
+	err    error
+}
+
+func (e *actionError) Error() string { return "Failed to " + e.action + ": " + e.err.Error() }
+func (e *actionError) Unwrap() error { return e.err }
@@ -0,0 +1,86 @@
+package linkwardenmcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/contextkey"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/linkwarden"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/mcpgo"
+)
+
+// newStubClient returns a client for a Linkwarden stub that answers
+// requests with the handler of the matching route, keyed by a ServeMux
+// pattern such as "GET /api/v1/tags". Other requests get a 404.
+func newStubClient(t *testing.T, routes map[string]http.HandlerFunc) *linkwarden.ClientWithResponses {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	for pattern, handler := range routes {
+		mux.HandleFunc(pattern, handler)
+	}
+	stub := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			mux.ServeHTTP(w, r)
+		},
+	))
+	t.Cleanup(stub.Close)
+
+	client, err := linkwarden.NewClientWithResponses(stub.URL)
+	require.NoError(t, err)
+	return client
+}
+
+// respondJSON returns a route handler answering with body
+func respondJSON(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+func TestNewToolReportsFailedRequestsByAction(t *testing.T) {
+	client := newStubClient(t, nil)
+
+	type args struct {
+		Id int `json:"id"`
+	}
+	denied := errors.New("access to collection 1 denied")
+
+	tool := newTool("get_link", "", "get link", client,
+		func(ctx context.Context, client *linkwarden.ClientWithResponses, req mcpgo.CallToolRequest, args args) (string, error) {
+			if args.Id == 0 {
+				return "", denied
+			}
+			resp, err := client.GetLinkWithResponse(ctx, args.Id)
+			if err != nil {
+				return "", requestFailed(err)
+			}
+			return "", unexpectedStatus(resp)
+		})
+
+	call := func(ctx context.Context, id int) string {
+		result, err := tool.GetHandler()(ctx, mcpgo.CallToolRequest{
+			Arguments: map[string]interface{}{"id": float64(id)},
+		})
+		require.NoError(t, err)
+		require.True(t, result.IsError)
+		return result.Text
+	}
+
+	assert.Equal(t, "Failed to get link: 404 Not Found", call(context.Background(), 1))
+	assert.Equal(t, denied.Error(), call(context.Background(), 0))
+
+	// A call for another instance uses that instance's client
+	other, err := linkwarden.NewClientWithResponses("http://127.0.0.1:1")
+	require.NoError(t, err)
+	ctx := contextkey.WithClient(context.Background(), other)
+	assert.Contains(t, call(ctx, 1), "Failed to get link: ")
+	assert.NotContains(t, call(ctx, 1), "404")
+}
@@ -12,80 +12,82 @@ import (
 func GetAllTags(
 	obs *observability.Observability,
 	client *linkwarden.ClientWithResponses,
+	access *AccessPolicy,
+	catalogs *Catalogs,
 ) mcpgo.Tool {
-	params := []mcpgo.ToolParameter{}
-
-	handler := func(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.ToolResult, error) {
-		client, err := getClientFromContextOrDefault(ctx, client)
-		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
+	handler := func(
+		ctx context.Context,
+		client *linkwarden.ClientWithResponses,
+		req mcpgo.CallToolRequest,
+		args struct{},
+	) (*linkwarden.TagsResponse, error) {
+		// Tags are shared by all collections and carry link counts, so
+		// they would reveal what hidden collections hold
+		if err := access.checkEverywhere("listing all tags", AccessRead); err != nil {
+			return nil, err
 		}
 
-		resp, err := client.GetTagsWithResponse(ctx)
+		tags, err := catalogs.catalogFor(client).Tags(ctx)
 		if err != nil {
-			return mcpgo.NewToolResultError("Failed to get all tags: " + err.Error()), nil
-		}
-
-		if resp.JSON200 != nil {
-			return mcpgo.NewToolResultJSON(resp.JSON200)
+			return nil, err
 		}
 
-		return mcpgo.NewToolResultError("Failed to get all tags: " + resp.Status()), nil
+		return &linkwarden.TagsResponse{Response: &tags}, nil
 	}
 
-	return mcpgo.NewTool(
+	return newTool(
 		"get_all_tags",
 		"Gets all tags.",
-		params,
+		"get all tags",
+		client,
 		handler,
 	)
 }
 
+// deleteTagByIdArgs are the arguments of delete_tag_by_id
+type deleteTagByIdArgs struct {
+	Id int `json:"id" description:"The ID of the tag to delete." required:"true"`
+}
+
 // DeleteTagById returns a tool for deleting a tag by ID
 func DeleteTagById(
 	obs *observability.Observability,
 	client *linkwarden.ClientWithResponses,
+	access *AccessPolicy,
+	catalogs *Catalogs,
 ) mcpgo.Tool {
-	params := []mcpgo.ToolParameter{
-		mcpgo.WithNumber(
-			"id",
-			mcpgo.Description("The ID of the tag to delete."),
-		),
-	}
-
-	handler := func(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.ToolResult, error) {
-		client, err := getClientFromContextOrDefault(ctx, client)
-		if err != nil {
-			return mcpgo.NewToolResultError(err.Error()), nil
+	handler := func(
+		ctx context.Context,
+		client *linkwarden.ClientWithResponses,
+		req mcpgo.CallToolRequest,
+		args deleteTagByIdArgs,
+	) (string, error) {
+		// Deleting a tag removes it from links in every collection
+		if err := access.checkEverywhere("deleting a tag", AccessWrite); err != nil {
+			return "", err
 		}
 
-		args := make(map[string]interface{})
-
-		validator := NewValidator(&req)
-		validator.ValidateAndAddRequiredInt(args, "id")
-
-		if result, err := validator.HandleErrorsIfAny(); result != nil {
-			return result, err
-		}
-
-		id := int(args["id"].(int64))
-
-		resp, err := client.DeleteTagWithResponse(ctx, id)
+		resp, err := client.DeleteTagWithResponse(ctx, args.Id)
 		if err != nil {
-			return mcpgo.NewToolResultError("Failed to delete tag: " + err.Error()), nil
+			return "", requestFailed(err)
 		}
 
 		if resp.StatusCode() == 200 {
-			return mcpgo.NewToolResultText("Tag deleted successfully"), nil
+			catalogs.catalogFor(client).InvalidateTags()
+			return "Tag deleted successfully", nil
 		}
 
-		return mcpgo.NewToolResultError("Failed to delete tag: " + resp.Status()), nil
+		return "", unexpectedStatus(resp)
 	}
 
-	return mcpgo.NewTool(
+	return newTool(
 		"delete_tag_by_id",
 		"Deletes a tag by its ID.",
-		params,
+		"delete tag",
+		client,
 		handler,
+		mcpgo.WithConfirmation(describeTagDeletion(client, access)),
+		mcpgo.WithDestructiveHint(true),
+		mcpgo.WithIdempotentHint(true),
 	)
 }
\ No newline at end of file
@@ -8,48 +8,78 @@ import (
 
 func NewToolSets(
 	obs *observability.Observability,
-	client *linkwarden.ClientWithResponses,
+	instances *Instances,
 	enabledToolsets []string,
 	readonly bool,
+	dynamic bool,
+	toolFilter toolsets.ToolFilter,
+	customization toolsets.ToolCustomization,
+	access *AccessPolicy,
+	catalogs *Catalogs,
 ) (*toolsets.ToolsetGroup, error) {
 	toolsetGroup := toolsets.NewToolsetGroup(readonly)
+	if dynamic {
+		toolsetGroup.EnableDynamicMode()
+	}
+
+	client := instances.Default()
 
 	search := toolsets.NewToolset("search", "Linkwarden search related tools").
-		AddReadTools(SearchLinks(obs, client))
+		AddReadTools(SearchLinks(obs, client, access))
 
 	collection := toolsets.NewToolset("collection", "Linkwarden collection related tools").
 		AddReadTools(
-			GetAllCollections(obs, client),
-			GetCollectionById(obs, client),
-			GetPublicCollectionsLinks(obs, client),
-			GetPublicCollectionsTags(obs, client),
-			GetPublicCollectionById(obs, client),
+			GetAllCollections(obs, client, access, catalogs),
+			GetCollectionById(obs, client, access),
+			GetPublicCollectionsLinks(obs, client, access),
+			GetPublicCollectionsTags(obs, client, access),
+			GetPublicCollectionById(obs, client, access),
 		).
 		AddWriteTools(
-			CreateCollection(obs, client),
-			DeleteCollectionById(obs, client),
+			CreateCollection(obs, client, access, catalogs),
+			DeleteCollectionById(obs, client, access, catalogs),
 		)
 
 	link := toolsets.NewToolset("link", "Linkwarden link related tools").
 		AddReadTools(
-			GetAllLinks(obs, client),
-			GetLinkById(obs, client),
+			GetAllLinks(obs, client, access),
+			GetLinkById(obs, client, access),
 		).
 		AddWriteTools(
-			CreateLink(obs, client),
-			DeleteLinkById(obs, client),
-			DeleteLinks(obs, client),
-			ArchiveLink(obs, client),
+			CreateLink(obs, client, access, catalogs),
+			DeleteLinkById(obs, client, access, catalogs),
+			DeleteLinks(obs, client, access, catalogs),
+			ArchiveLink(obs, client, access),
 		)
 
 	tags := toolsets.NewToolset("tags", "Linkwarden tag related tools").
 		AddReadTools(
-			GetAllTags(obs, client),
+			GetAllTags(obs, client, access, catalogs),
 		).
 		AddWriteTools(
-			DeleteTagById(obs, client),
+			DeleteTagById(obs, client, access, catalogs),
 		)
 
+	linkwardenToolsets := []*toolsets.Toolset{search, collection, link, tags}
+	if instances.multiple() {
+		// Every Linkwarden tool can be pointed at another instance
+		for _, toolset := range linkwardenToolsets {
+			toolset.CustomizeTools(instances.routing())
+		}
+
+		// The assistant needs list_instances to choose an instance,
+		// whichever toolsets are enabled
+		toolsetGroup.AddToolset(toolsets.NewToolset("instances", "Linkwarden instance related tools").
+			AddReadTools(ListInstances(obs, instances)).
+			SetAlwaysEnabled())
+	}
+
+	// Like the tools using it, the cache is there whichever toolsets are
+	// enabled
+	toolsetGroup.AddToolset(toolsets.NewToolset("cache", "Linkwarden cache related tools").
+		AddReadTools(RefreshCache(obs, catalogs)).
+		SetAlwaysEnabled())
+
 	toolsetGroup.AddToolset(search)
 	toolsetGroup.AddToolset(collection)
 	toolsetGroup.AddToolset(link)
@@ -59,5 +89,44 @@ func NewToolSets(
 		return nil, err
 	}
 
+	if err := toolsetGroup.SetToolFilter(toolFilter); err != nil {
+		return nil, err
+	}
+
+	if err := toolsetGroup.SetToolCustomization(customization); err != nil {
+		return nil, err
+	}
+
 	return toolsetGroup, nil
 }
+
+// ToolsetNames returns the sorted names of the toolsets that can be
+// enabled, which depend on whether several instances are configured
+func ToolsetNames(multipleInstances bool) []string {
+	names := []string{"default"}
+	if multipleInstances {
+		names = append(names, "other")
+	}
+
+	// The toolsets are built but never called, so the clients need no
+	// real instance
+	var list []Instance
+	for _, name := range names {
+		client, err := linkwarden.NewClientWithResponses("http://localhost")
+		if err != nil {
+			panic(err)
+		}
+		list = append(list, Instance{Name: name, Client: client})
+	}
+	instances, err := NewInstances("default", list...)
+	if err != nil {
+		panic(err)
+	}
+
+	toolsetGroup, err := NewToolSets(observability.New(), instances, nil, false, true,
+		toolsets.ToolFilter{}, toolsets.ToolCustomization{}, nil, nil)
+	if err != nil {
+		panic(err)
+	}
+	return toolsetGroup.Names()
+}
@@ -0,0 +1,146 @@
+package linkwardenmcp
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/log"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/mcpgo"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/observability"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/toolsets"
+)
+
+// newTestServer creates a server with every toolset enabled, backed by a
+// Linkwarden stub that fails every request and counts them
+func newTestServer(t *testing.T) (*mcpgo.Mark3labsImpl, *atomic.Int64) {
+	t.Helper()
+
+	requests := &atomic.Int64{}
+	client := newStubClient(t, map[string]http.HandlerFunc{
+		"/": func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			w.WriteHeader(http.StatusInternalServerError)
+		},
+	})
+
+	instances, err := NewInstances("", Instance{Name: "default", Client: client})
+	require.NoError(t, err)
+
+	logger, err := log.NewSlogger()
+	require.NoError(t, err)
+	obs := observability.New(observability.WithLogging(logger))
+
+	srv, _, err := NewLinkwardenMcpServer(obs, instances, nil, false, false, toolsets.ToolFilter{}, toolsets.ToolCustomization{}, nil, nil)
+	require.NoError(t, err)
+
+	impl, ok := srv.(*mcpgo.Mark3labsImpl)
+	require.True(t, ok)
+
+	return impl, requests
+}
+
+// sampleArguments returns a value for every required parameter of the
+// schema, except the one named skip
+func sampleArguments(schema mcp.ToolInputSchema, skip string) map[string]interface{} {
+	args := map[string]interface{}{}
+	for _, name := range schema.Required {
+		if name != skip {
+			args[name] = sampleValue(schema.Properties[name].(map[string]any))
+		}
+	}
+	return args
+}
+
+// sampleValue returns a value matching the JSON schema of a parameter
+func sampleValue(schema map[string]any) interface{} {
+	if enum, ok := schema["enum"].([]string); ok && len(enum) > 0 {
+		return enum[0]
+	}
+	switch schema["type"] {
+	case "integer", "number":
+		return 1
+	case "boolean":
+		return true
+	case "array":
+		items, _ := schema["items"].(map[string]any)
+		return []interface{}{sampleValue(items)}
+	case "object":
+		return map[string]interface{}{}
+	default:
+		return "https://example.com"
+	}
+}
+
+// requiredParameters lists the parameters each tool cannot do without, as
+// documented in docs/tools-reference.md
+var requiredParameters = map[string][]string{
+	"archive_link":                 {"id"},
+	"create_collection":            {},
+	"create_link":                  {"name", "url"},
+	"delete_collection_by_id":      {"id"},
+	"delete_link_by_id":            {"id"},
+	"delete_links":                 {"linkIds"},
+	"delete_tag_by_id":             {"id"},
+	"get_all_collections":          {},
+	"get_all_links":                {},
+	"get_all_tags":                 {},
+	"get_collection_by_id":         {"id"},
+	"get_link_by_id":               {"id"},
+	"get_public_collection_by_id":  {"id"},
+	"get_public_collections_links": {"collectionId"},
+	"get_public_collections_tags":  {"collectionId"},
+	"refresh_cache":                {},
+	"search_links":                 {},
+}
+
+func TestToolsRequireTheirParameters(t *testing.T) {
+	srv, requests := newTestServer(t)
+
+	tools := srv.McpServer.ListTools()
+	require.Len(t, tools, len(requiredParameters))
+
+	for name, tool := range tools {
+		required, ok := requiredParameters[name]
+		require.True(t, ok, "required parameters of %s are not listed", name)
+		assert.ElementsMatch(t, required, tool.Tool.InputSchema.Required, name)
+
+		// Without a required parameter, the call fails before Linkwarden
+		// is contacted
+		for _, param := range required {
+			req := mcp.CallToolRequest{}
+			req.Params.Name = name
+			req.Params.Arguments = sampleArguments(tool.Tool.InputSchema, param)
+
+			before := requests.Load()
+			result, err := tool.Handler(context.Background(), req)
+			require.NoError(t, err)
+			require.True(t, result.IsError, "%s without %s", name, param)
+			assert.Equal(t, "Validation errors:\n- missing required parameter: "+param,
+				result.Content[0].(mcp.TextContent).Text)
+			assert.Equal(t, before, requests.Load(), "%s without %s", name, param)
+		}
+	}
+}
+
+func TestToolsAdvertiseIntegerIDs(t *testing.T) {
+	srv, _ := newTestServer(t)
+
+	for name, tool := range srv.McpServer.ListTools() {
+		for param, schema := range tool.Tool.InputSchema.Properties {
+			if param != "id" && param != "collectionId" && param != "tagId" {
+				continue
+			}
+
+			prop, ok := schema.(map[string]any)
+			require.True(t, ok)
+			assert.Equal(t, "integer", prop["type"],
+				"%s.%s should be an integer", name, param)
+		}
+	}
+}
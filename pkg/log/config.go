@@ -60,6 +60,14 @@ func WithLogLevel(level slog.Level) ConfigOption {
 	}
 }
 
+// WithLogLevelVar sets a log level that can be changed while the logger
+// is in use
+func WithLogLevelVar(level *slog.LevelVar) ConfigOption {
+	return func(c *Config) {
+		c.slog.logLevel = level
+	}
+}
+
 // NewConfig...
 func NewConfig(opts ...ConfigOption) *Config {
 	config := &Config{
@@ -29,7 +29,7 @@ func New(ctx context.Context, config *Config) (context.Context, Logger) {
 	switch config.GetMode() {
 	case ModeStdio:
 		// For stdio mode, use slog logger that writes to file
-		logger, err = NewSloggerWithFile(config.GetSlogConfig().GetPath())
+		logger, err = newSloggerWithFile(config.GetSlogConfig().GetPath(), config.GetLogLevel())
 		if err != nil {
 			fmt.Printf("failed to initialize logger\n")
 			os.Exit(1)
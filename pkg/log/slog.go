@@ -3,6 +3,7 @@ package log
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"log/slog"
 	"os"
@@ -119,6 +120,20 @@ func NewSloggerWithStdout(config *Config) (*slogLogger, error) {
 	}, nil
 }
 
+// NewDiscardLogger returns a Logger that drops every entry, for commands
+// that report to the user directly
+func NewDiscardLogger() Logger {
+	return &slogLogger{
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		closer: func() error { return nil },
+	}
+}
+
+// DefaultLogPath returns where logs are written when no log file is set
+func DefaultLogPath() string {
+	return getDefaultLogPath()
+}
+
 // getDefaultLogPath returns an absolute path for the logs directory
 func getDefaultLogPath() string {
 	execPath, err := os.Executable()
@@ -137,6 +152,13 @@ func getDefaultLogPath() string {
 // logger uses a default path next to the executable
 // If the log file cannot be opened, falls back to stderr
 func NewSloggerWithFile(path string) (*slogLogger, error) {
+	return newSloggerWithFile(path, slog.LevelInfo)
+}
+
+// newSloggerWithFile returns a file logger writing entries at level and
+// above
+func newSloggerWithFile(path string, level slog.Leveler) (*slogLogger, error) {
+	opts := &slog.HandlerOptions{Level: level}
 	if path == "" {
 		path = getDefaultLogPath()
 	}
@@ -149,7 +171,7 @@ func NewSloggerWithFile(path string) (*slogLogger, error) {
 			"Warning: Failed to open log file: %v\nFalling back to stderr\n",
 			err,
 		)
-		logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+		logger := slog.New(slog.NewTextHandler(os.Stderr, opts))
 		noop := func() error { return nil }
 		return &slogLogger{
 			logger: logger,
@@ -159,7 +181,7 @@ func NewSloggerWithFile(path string) (*slogLogger, error) {
 
 	fmt.Fprintf(os.Stderr, "logs are stored in: %v\n", path)
 	return &slogLogger{
-		logger: slog.New(slog.NewTextHandler(file, nil)),
+		logger: slog.New(slog.NewTextHandler(file, opts)),
 		closer: func() error {
 			if err := file.Close(); err != nil {
 				log.Printf("close log file: %v", err)
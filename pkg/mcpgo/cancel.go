@@ -0,0 +1,205 @@
+package mcpgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// methodNotificationCancelled is the MCP method clients use to cancel
+// a request that is still in flight
+const methodNotificationCancelled = "notifications/cancelled"
+
+// requestIDMetaKey is the _meta field carrying the JSON-RPC request ID of
+// a tool call to its handler, since mcp-go does not expose it there
+const requestIDMetaKey = "linkwarden-mcp-server/requestId"
+
+// toolTimeouts holds the default tool timeout and per-tool overrides
+type toolTimeouts struct {
+	defaultTimeout time.Duration
+	overrides      map[string]time.Duration
+}
+
+// timeoutFor returns the timeout for the tool. A configured override,
+// keyed by the tool name with or without prefix, wins over the tool's own
+// timeout, which wins over the default. Zero means no timeout.
+func (t toolTimeouts) timeoutFor(tool Tool) time.Duration {
+	if timeout, ok := t.overrides[tool.GetName()]; ok {
+		return timeout
+	}
+	if timeout, ok := t.overrides[tool.baseName()]; ok {
+		return timeout
+	}
+	if timeout := tool.GetTimeout(); timeout > 0 {
+		return timeout
+	}
+	return t.defaultTimeout
+}
+
+// WithToolTimeouts returns a server option that bounds every tool call by
+// defaultTimeout, or by the entry in overrides keyed by tool name.
+// A zero timeout disables the limit.
+func WithToolTimeouts(
+	defaultTimeout time.Duration,
+	overrides map[string]time.Duration,
+) ServerOption {
+	return func(s OptionSetter) error {
+		return s.SetOption(toolTimeouts{
+			defaultTimeout: defaultTimeout,
+			overrides:      overrides,
+		})
+	}
+}
+
+// inflightRequests tracks the cancel functions of running tool calls
+type inflightRequests struct {
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+func newInflightRequests() *inflightRequests {
+	return &inflightRequests{
+		cancels: make(map[string]context.CancelFunc),
+	}
+}
+
+// requestKey normalizes a JSON-RPC request ID, which may be a number or
+// a string, into a map key
+func requestKey(id any) string {
+	return fmt.Sprint(id)
+}
+
+func (r *inflightRequests) add(id any, cancel context.CancelFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cancels[requestKey(id)] = cancel
+}
+
+func (r *inflightRequests) remove(id any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.cancels, requestKey(id))
+}
+
+// cancel cancels the request with the given ID, if it is still running
+func (r *inflightRequests) cancel(id any) {
+	r.mu.Lock()
+	cancel, ok := r.cancels[requestKey(id)]
+	r.mu.Unlock()
+
+	if ok {
+		cancel()
+	}
+}
+
+// tagRequestID is a before-call-tool hook that records the JSON-RPC
+// request ID in the request's _meta so the handler can be cancelled
+func tagRequestID(_ context.Context, id any, message *mcp.CallToolRequest) {
+	if message.Params.Meta == nil {
+		message.Params.Meta = &mcp.Meta{}
+	}
+	if message.Params.Meta.AdditionalFields == nil {
+		message.Params.Meta.AdditionalFields = make(map[string]any)
+	}
+	message.Params.Meta.AdditionalFields[requestIDMetaKey] = id
+}
+
+// requestIDFromMeta returns the request ID recorded by tagRequestID
+func requestIDFromMeta(req mcp.CallToolRequest) (any, bool) {
+	if req.Params.Meta == nil {
+		return nil, false
+	}
+	id, ok := req.Params.Meta.AdditionalFields[requestIDMetaKey]
+	return id, ok && id != nil
+}
+
+// handleCancelled cancels the tool call named by a notifications/cancelled
+func (s *Mark3labsImpl) handleCancelled(
+	_ context.Context, notification mcp.JSONRPCNotification) {
+	id, ok := notification.Params.AdditionalFields["requestId"]
+	if !ok || id == nil {
+		return
+	}
+	s.inflight.cancel(id)
+}
+
+// withCancellation wraps a tool handler so that it stops when the client
+// cancels the request
+func (s *Mark3labsImpl) withCancellation(
+	name string,
+	handler server.ToolHandlerFunc,
+) server.ToolHandlerFunc {
+	return func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		if id, ok := requestIDFromMeta(req); ok {
+			s.inflight.add(id, cancel)
+			defer s.inflight.remove(id)
+		}
+
+		result, err := handler(ctx, req)
+		if ctx.Err() != nil && (err != nil || result == nil || result.IsError) {
+			return mcp.NewToolResultError(contextErrorMessage(name, 0, ctx.Err())), nil
+		}
+		return result, err
+	}
+}
+
+// withTimeout returns a middleware that bounds the tool handler by the
+// given timeout, and stops it once the call is cancelled. It runs after
+// confirmation, so time spent waiting for the user does not count.
+func withTimeout(timeout time.Duration) ToolMiddleware {
+	return func(tool Tool, next ToolHandler) ToolHandler {
+		name := tool.GetName()
+		return func(ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+
+			type outcome struct {
+				result *ToolResult
+				err    error
+			}
+
+			// Run the handler separately so a handler that ignores ctx
+			// cannot keep the call hanging past its deadline
+			done := make(chan outcome, 1)
+			go func() {
+				result, err := next(ctx, req)
+				done <- outcome{result: result, err: err}
+			}()
+
+			select {
+			case o := <-done:
+				if o.err == nil && o.result != nil && !o.result.IsError {
+					return o.result, nil
+				}
+				if ctx.Err() != nil {
+					return NewToolResultError(contextErrorMessage(name, timeout, ctx.Err())), nil
+				}
+				return o.result, o.err
+			case <-ctx.Done():
+				return NewToolResultError(contextErrorMessage(name, timeout, ctx.Err())), nil
+			}
+		}
+	}
+}
+
+// contextErrorMessage describes why a tool call stopped early
+func contextErrorMessage(name string, timeout time.Duration, err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Sprintf("Tool %s timed out after %gs", name, timeout.Seconds())
+	}
+	return fmt.Sprintf("Tool %s was cancelled", name)
+}
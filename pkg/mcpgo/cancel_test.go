@@ -0,0 +1,170 @@
+package mcpgo
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingTool returns a tool whose handler signals started and then
+// waits until its context is done
+func blockingTool(name string, started chan<- struct{}, opts ...ToolOption) Tool {
+	return NewTool(name, "", nil,
+		func(ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+			if started != nil {
+				started <- struct{}{}
+			}
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}, opts...)
+}
+
+// callTool sends a tools/call for the named tool and returns its result
+func callTool(t *testing.T, srv *Mark3labsImpl, id int, name string) *mcp.CallToolResult {
+	t.Helper()
+	request, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"method":  "tools/call",
+		"params":  map[string]any{"name": name},
+	})
+	require.NoError(t, err)
+
+	return toolResult(t, srv.McpServer.HandleMessage(context.Background(), request))
+}
+
+// toolResult extracts the tool result from a JSON-RPC response, which
+// mcp-go fills with either a value or a pointer
+func toolResult(t *testing.T, message mcp.JSONRPCMessage) *mcp.CallToolResult {
+	t.Helper()
+	response, ok := message.(mcp.JSONRPCResponse)
+	require.True(t, ok, "expected a JSON-RPC response")
+	switch result := response.Result.(type) {
+	case *mcp.CallToolResult:
+		return result
+	case mcp.CallToolResult:
+		return &result
+	}
+	require.Failf(t, "expected a tool result", "got %T", response.Result)
+	return nil
+}
+
+func resultText(t *testing.T, result *mcp.CallToolResult) string {
+	t.Helper()
+	require.Len(t, result.Content, 1)
+	text, ok := result.Content[0].(mcp.TextContent)
+	require.True(t, ok)
+	return text.Text
+}
+
+func TestTimeoutFor(t *testing.T) {
+	timeouts := toolTimeouts{
+		defaultTimeout: time.Minute,
+		overrides:      map[string]time.Duration{"slow": time.Hour},
+	}
+
+	plain := NewTool("plain", "", nil, nil)
+	assert.Equal(t, time.Minute, timeouts.timeoutFor(plain))
+
+	own := NewTool("own", "", nil, nil, WithTimeout(5*time.Second))
+	assert.Equal(t, 5*time.Second, timeouts.timeoutFor(own))
+
+	overridden := NewTool("slow", "", nil, nil, WithTimeout(5*time.Second))
+	assert.Equal(t, time.Hour, timeouts.timeoutFor(overridden))
+
+	assert.Zero(t, toolTimeouts{}.timeoutFor(plain))
+}
+
+func TestToolCallTimesOut(t *testing.T) {
+	srv := NewMcpServer("test", "1.0.0", WithToolTimeouts(
+		10*time.Millisecond,
+		map[string]time.Duration{"patient": 50 * time.Millisecond},
+	))
+	srv.AddTools(blockingTool("impatient", nil), blockingTool("patient", nil))
+
+	result := callTool(t, srv, 1, "impatient")
+	assert.True(t, result.IsError)
+	assert.Equal(t, "Tool impatient timed out after 0.01s", resultText(t, result))
+
+	result = callTool(t, srv, 2, "patient")
+	assert.True(t, result.IsError)
+	assert.Equal(t, "Tool patient timed out after 0.05s", resultText(t, result))
+}
+
+func TestCancelledNotificationStopsToolCall(t *testing.T) {
+	srv := NewMcpServer("test", "1.0.0")
+	started := make(chan struct{}, 1)
+	srv.AddTools(blockingTool("wait", started))
+
+	responses := make(chan mcp.JSONRPCMessage, 1)
+	go func() {
+		responses <- srv.McpServer.HandleMessage(context.Background(), json.RawMessage(
+			`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"wait"}}`))
+	}()
+
+	<-started
+	srv.inflight.mu.Lock()
+	assert.Contains(t, srv.inflight.cancels, "7")
+	srv.inflight.mu.Unlock()
+
+	srv.McpServer.HandleMessage(context.Background(), json.RawMessage(
+		`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":7}}`))
+
+	result := toolResult(t, <-responses)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "Tool wait was cancelled", resultText(t, result))
+
+	srv.inflight.mu.Lock()
+	defer srv.inflight.mu.Unlock()
+	assert.Empty(t, srv.inflight.cancels)
+}
+
+func TestCancelledNotificationForUnknownRequestIsIgnored(t *testing.T) {
+	srv := NewMcpServer("test", "1.0.0")
+	srv.AddTools(NewTool("quick", "", nil,
+		func(ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+			return NewToolResultText("done"), nil
+		}))
+
+	srv.McpServer.HandleMessage(context.Background(), json.RawMessage(
+		`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":99}}`))
+
+	result := callTool(t, srv, 1, "quick")
+	assert.False(t, result.IsError)
+	assert.Equal(t, "done", resultText(t, result))
+	assert.Empty(t, srv.inflight.cancels)
+}
+
+func TestToolTimeoutStartsAfterConfirmation(t *testing.T) {
+	srv := NewMcpServer("test", "1.0.0",
+		WithToolConfirmation(true),
+		WithToolTimeouts(20*time.Millisecond, nil))
+	srv.AddTools(NewTool("drop", "", nil,
+		func(ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+			return NewToolResultText("dropped"), nil
+		},
+		WithConfirmation(func(ctx context.Context, req CallToolRequest) (string, *ToolResult, error) {
+			return "Drop?", nil, nil
+		})))
+
+	// The user takes longer to answer than the tool may run
+	session := &elicitingSession{
+		capabilities: elicitationCapable(),
+		delay:        100 * time.Millisecond,
+		result: &mcp.ElicitationResult{ElicitationResponse: mcp.ElicitationResponse{
+			Action:  mcp.ElicitationResponseActionAccept,
+			Content: map[string]any{"confirm": true},
+		}},
+	}
+	ctx := srv.McpServer.WithContext(context.Background(), session)
+	result := toolResult(t, srv.McpServer.HandleMessage(ctx, json.RawMessage(
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"drop","arguments":{}}}`)))
+
+	assert.False(t, result.IsError)
+	assert.Equal(t, "dropped", resultText(t, result))
+}
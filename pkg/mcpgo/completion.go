@@ -0,0 +1,97 @@
+package mcpgo
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxCompletionValues is the maximum number of values MCP allows in
+// a completion result
+const maxCompletionValues = 100
+
+// completionTimeout bounds how long a completion request may take. mcp-go
+// answers them in line with other messages, so a slow Linkwarden must not
+// hold up the session for long.
+const completionTimeout = 10 * time.Second
+
+// ArgumentCompleter suggests values for an argument given the partial
+// value typed so far
+type ArgumentCompleter func(ctx context.Context, value string) ([]string, error)
+
+// completionRegistry holds the argument completers of prompts and answers
+// completion/complete requests for them. MCP only defines completions for
+// prompt and resource arguments, so tools have none.
+type completionRegistry struct {
+	mu         sync.RWMutex
+	completers map[string]map[string]ArgumentCompleter
+}
+
+func newCompletionRegistry() *completionRegistry {
+	return &completionRegistry{
+		completers: make(map[string]map[string]ArgumentCompleter),
+	}
+}
+
+// add registers the completer for an argument of the given prompt
+func (r *completionRegistry) add(
+	prompt, argument string, completer ArgumentCompleter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.completers[prompt] == nil {
+		r.completers[prompt] = make(map[string]ArgumentCompleter)
+	}
+	r.completers[prompt][argument] = completer
+}
+
+// get returns the completer for an argument, if any
+func (r *completionRegistry) get(
+	prompt, argument string) (ArgumentCompleter, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	completer, ok := r.completers[prompt][argument]
+	return completer, ok
+}
+
+// CompletePromptArgument implements server.PromptCompletionProvider.
+// Arguments without a completer get an empty list of values.
+func (r *completionRegistry) CompletePromptArgument(
+	ctx context.Context,
+	prompt string,
+	argument mcp.CompleteArgument,
+	_ mcp.CompleteContext,
+) (*mcp.Completion, error) {
+	var values []string
+	if completer, ok := r.get(prompt, argument.Name); ok {
+		ctx, cancel := context.WithTimeout(ctx, completionTimeout)
+		defer cancel()
+
+		var err error
+		values, err = completer(ctx, argument.Value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return newCompletion(values), nil
+}
+
+// newCompletion builds a completion, truncating the values to the maximum
+// MCP allows
+func newCompletion(values []string) *mcp.Completion {
+	completion := &mcp.Completion{Values: []string{}, Total: len(values)}
+
+	if len(values) > maxCompletionValues {
+		values = values[:maxCompletionValues]
+		completion.HasMore = true
+	}
+	if len(values) > 0 {
+		completion.Values = values
+	}
+
+	return completion
+}
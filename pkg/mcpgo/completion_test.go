@@ -0,0 +1,54 @@
+package mcpgo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromptArgumentCompletion(t *testing.T) {
+	srv := NewMcpServer("test", "1.0.0")
+	srv.AddPrompts(NewPrompt("summarize", "", []PromptArgument{
+		WithArgument("name", CompleteWith(func(ctx context.Context, value string) ([]string, error) {
+			var values []string
+			for i := range 150 {
+				values = append(values, fmt.Sprintf("%s%d", value, i))
+			}
+			return values, nil
+		})),
+		WithArgument("style"),
+	}, nil))
+
+	complete := func(ref, argument string) mcp.JSONRPCMessage {
+		return srv.McpServer.HandleMessage(context.Background(), json.RawMessage(
+			`{"jsonrpc":"2.0","id":1,"method":"completion/complete","params":`+
+				`{"ref":`+ref+`,"argument":{"name":"`+argument+`","value":"r"}}}`))
+	}
+	completion := func(message mcp.JSONRPCMessage) mcp.Completion {
+		response, ok := message.(mcp.JSONRPCResponse)
+		require.True(t, ok, "expected a JSON-RPC response, got %T", message)
+		result, ok := response.Result.(mcp.CompleteResult)
+		require.True(t, ok, "expected a completion result, got %T", response.Result)
+		return result.Completion
+	}
+
+	// Values are capped at the MCP maximum
+	got := completion(complete(`{"type":"ref/prompt","name":"summarize"}`, "name"))
+	assert.Len(t, got.Values, maxCompletionValues)
+	assert.Equal(t, "r0", got.Values[0])
+	assert.Equal(t, 150, got.Total)
+	assert.True(t, got.HasMore)
+
+	// Arguments without a completer get no values
+	got = completion(complete(`{"type":"ref/prompt","name":"summarize"}`, "style"))
+	assert.Equal(t, []string{}, got.Values)
+
+	// MCP defines no completions for tool arguments
+	_, ok := complete(`{"type":"ref/tool","name":"summarize"}`, "name").(mcp.JSONRPCError)
+	assert.True(t, ok)
+}
@@ -0,0 +1,164 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// confirmParameter is the argument that confirms a call when the client
+// cannot be asked through elicitation
+const confirmParameter = "confirm"
+
+// ConfirmationDescriber describes what a tool call is about to do so the
+// user can confirm it. It is called with the validated arguments. A
+// non-nil result aborts the call and is returned instead, e.g. when the
+// caller may not make it.
+type ConfirmationDescriber func(
+	ctx context.Context,
+	request CallToolRequest) (string, *ToolResult, error)
+
+// confirmationPolicy holds whether tools with a describer need confirmation
+type confirmationPolicy struct {
+	enabled bool
+}
+
+// WithToolConfirmation returns a server option that makes tools registered with
+// a ConfirmationDescriber ask the user before they run. Clients supporting
+// elicitation are asked directly, others must pass confirm: true.
+func WithToolConfirmation(enabled bool) ServerOption {
+	return func(s OptionSetter) error {
+		if err := s.SetOption(confirmationPolicy{enabled: enabled}); err != nil {
+			return err
+		}
+		if !enabled {
+			return nil
+		}
+		return s.SetOption(server.WithElicitation())
+	}
+}
+
+// addConfirmParameter advertises the confirm argument in the tool's schema
+func addConfirmParameter(tool *mcp.Tool) {
+	if tool.InputSchema.Properties == nil {
+		tool.InputSchema.Properties = make(map[string]any)
+	}
+	tool.InputSchema.Properties[confirmParameter] = map[string]any{
+		"type": "boolean",
+		"description": "Set to true to confirm the operation. Only needed " +
+			"when the client cannot ask the user for confirmation.",
+	}
+}
+
+// confirmedByArgumentKey is the context key of the confirm argument
+type confirmedByArgumentKey struct{}
+
+// withConfirmArgument wraps a tool handler so that the confirm argument,
+// which is not one of the tool's own parameters, is moved from the
+// arguments into the context before they are validated
+func withConfirmArgument(handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+		if value, ok := args[confirmParameter]; ok {
+			confirmed, _ := value.(bool)
+			ctx = context.WithValue(ctx, confirmedByArgumentKey{}, confirmed)
+
+			withoutConfirm := make(map[string]any, len(args))
+			for name, value := range args {
+				if name != confirmParameter {
+					withoutConfirm[name] = value
+				}
+			}
+			req.Params.Arguments = withoutConfirm
+		}
+		return handler(ctx, req)
+	}
+}
+
+// withConfirmation returns a middleware that only runs the tool once the
+// user confirmed the call described by describe
+func withConfirmation(describe ConfirmationDescriber) ToolMiddleware {
+	return func(tool Tool, next ToolHandler) ToolHandler {
+		name := tool.GetName()
+		return func(ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+			description, result, err := describe(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+			if result != nil {
+				return NewToolResultError(result.Text), nil
+			}
+
+			if !supportsElicitation(ctx) {
+				if confirmed, _ := ctx.Value(confirmedByArgumentKey{}).(bool); confirmed {
+					return next(ctx, req)
+				}
+				return NewToolResultError(fmt.Sprintf(
+					"Tool %s needs confirmation: %s Call it again with "+
+						"confirm: true once the user has agreed.",
+					name, description)), nil
+			}
+
+			confirmed, err := requestConfirmation(ctx, description)
+			if err != nil {
+				return NewToolResultError(fmt.Sprintf(
+					"Failed to confirm %s: %s", name, err.Error())), nil
+			}
+			if !confirmed {
+				return NewToolResultError(fmt.Sprintf(
+					"Tool %s was not confirmed by the user", name)), nil
+			}
+
+			return next(ctx, req)
+		}
+	}
+}
+
+// supportsElicitation reports whether the client of the current session
+// declared the elicitation capability
+func supportsElicitation(ctx context.Context) bool {
+	session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithClientInfo)
+	if !ok {
+		return false
+	}
+	return session.GetClientCapabilities().Elicitation != nil
+}
+
+// requestConfirmation asks the user to confirm the described action
+func requestConfirmation(ctx context.Context, description string) (bool, error) {
+	mcpServer := server.ServerFromContext(ctx)
+	if mcpServer == nil {
+		return false, fmt.Errorf("no server found in context")
+	}
+
+	request := mcp.ElicitationRequest{}
+	request.Params.Message = description
+	request.Params.RequestedSchema = map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			confirmParameter: map[string]any{
+				"type":        "boolean",
+				"title":       "Confirm",
+				"description": "Proceed with the operation",
+			},
+		},
+		"required": []string{confirmParameter},
+	}
+
+	result, err := mcpServer.RequestElicitation(ctx, request)
+	if err != nil {
+		return false, err
+	}
+	if result.Action != mcp.ElicitationResponseActionAccept {
+		return false, nil
+	}
+
+	content, _ := result.Content.(map[string]any)
+	confirmed, _ := content[confirmParameter].(bool)
+	return confirmed, nil
+}
@@ -0,0 +1,214 @@
+package mcpgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// elicitingSession is a client session that answers elicitation requests
+// with a fixed result after delay, if it declared the capability
+type elicitingSession struct {
+	capabilities mcp.ClientCapabilities
+	result       *mcp.ElicitationResult
+	err          error
+	delay        time.Duration
+	requests     []mcp.ElicitationRequest
+}
+
+func (s *elicitingSession) Initialize()       {}
+func (s *elicitingSession) Initialized() bool { return true }
+func (s *elicitingSession) SessionID() string { return "test" }
+func (s *elicitingSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return make(chan mcp.JSONRPCNotification, 10)
+}
+func (s *elicitingSession) GetClientInfo() mcp.Implementation            { return mcp.Implementation{} }
+func (s *elicitingSession) SetClientInfo(mcp.Implementation)             {}
+func (s *elicitingSession) SetClientCapabilities(mcp.ClientCapabilities) {}
+func (s *elicitingSession) GetClientCapabilities() mcp.ClientCapabilities {
+	return s.capabilities
+}
+func (s *elicitingSession) RequestElicitation(
+	_ context.Context, request mcp.ElicitationRequest) (*mcp.ElicitationResult, error) {
+	s.requests = append(s.requests, request)
+	time.Sleep(s.delay)
+	return s.result, s.err
+}
+
+// newConfirmedServer returns a server with a "drop" tool that needs
+// confirmation, and the arguments of every call that ran
+func newConfirmedServer(t *testing.T) (*Mark3labsImpl, *[]map[string]any) {
+	t.Helper()
+	var runs []map[string]any
+	srv := NewMcpServer("test", "1.0.0", WithToolConfirmation(true))
+	srv.AddTools(NewTool("drop", "", []ToolParameter{WithString("name")},
+		func(ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+			args, _ := req.Arguments.(map[string]any)
+			runs = append(runs, args)
+			return NewToolResultText("dropped"), nil
+		},
+		WithConfirmation(func(ctx context.Context, req CallToolRequest) (string, *ToolResult, error) {
+			args, _ := req.Arguments.(map[string]any)
+			name, ok := args["name"].(string)
+			if !ok {
+				return "", NewToolResultError("missing name"), nil
+			}
+			return "Drop " + name + "?", nil, nil
+		})))
+	return srv, &runs
+}
+
+func callWithSession(
+	t *testing.T, srv *Mark3labsImpl, session *elicitingSession, args string) *mcp.CallToolResult {
+	t.Helper()
+	ctx := srv.McpServer.WithContext(context.Background(), session)
+	return toolResult(t, srv.McpServer.HandleMessage(ctx, json.RawMessage(
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"drop","arguments":`+args+`}}`)))
+}
+
+func elicitationCapable() mcp.ClientCapabilities {
+	return mcp.ClientCapabilities{Elicitation: &mcp.ElicitationCapability{}}
+}
+
+func TestConfirmationByElicitation(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  *mcp.ElicitationResult
+		err     error
+		text    string
+		confirm bool
+	}{
+		{
+			name: "accepted",
+			result: &mcp.ElicitationResult{ElicitationResponse: mcp.ElicitationResponse{
+				Action:  mcp.ElicitationResponseActionAccept,
+				Content: map[string]any{"confirm": true},
+			}},
+			text:    "dropped",
+			confirm: true,
+		},
+		{
+			name: "accepted without confirming",
+			result: &mcp.ElicitationResult{ElicitationResponse: mcp.ElicitationResponse{
+				Action:  mcp.ElicitationResponseActionAccept,
+				Content: map[string]any{"confirm": false},
+			}},
+			text: "Tool drop was not confirmed by the user",
+		},
+		{
+			name: "declined",
+			result: &mcp.ElicitationResult{ElicitationResponse: mcp.ElicitationResponse{
+				Action: mcp.ElicitationResponseActionDecline,
+			}},
+			text: "Tool drop was not confirmed by the user",
+		},
+		{
+			name: "cancelled",
+			result: &mcp.ElicitationResult{ElicitationResponse: mcp.ElicitationResponse{
+				Action: mcp.ElicitationResponseActionCancel,
+			}},
+			text: "Tool drop was not confirmed by the user",
+		},
+		{
+			name: "failed",
+			err:  errors.New("client went away"),
+			text: "Failed to confirm drop: client went away",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, runs := newConfirmedServer(t)
+			session := &elicitingSession{
+				capabilities: elicitationCapable(),
+				result:       tt.result,
+				err:          tt.err,
+			}
+
+			result := callWithSession(t, srv, session, `{"name":"cache","confirm":true}`)
+
+			require.Len(t, session.requests, 1)
+			assert.Equal(t, "Drop cache?", session.requests[0].Params.Message)
+			assert.Equal(t, tt.text, resultText(t, result))
+			assert.Equal(t, !tt.confirm, result.IsError)
+			if tt.confirm {
+				assert.Equal(t, []map[string]any{{"name": "cache"}}, *runs)
+			} else {
+				assert.Empty(t, *runs)
+			}
+		})
+	}
+}
+
+func TestConfirmationByArgumentWithoutElicitation(t *testing.T) {
+	srv, runs := newConfirmedServer(t)
+	session := &elicitingSession{}
+
+	result := callWithSession(t, srv, session, `{"name":"cache"}`)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "Tool drop needs confirmation: Drop cache? Call it again with "+
+		"confirm: true once the user has agreed.", resultText(t, result))
+	assert.Empty(t, *runs)
+
+	result = callWithSession(t, srv, session, `{"name":"cache","confirm":true}`)
+	assert.False(t, result.IsError)
+	assert.Equal(t, []map[string]any{{"name": "cache"}}, *runs)
+	assert.Empty(t, session.requests)
+}
+
+func TestConfirmationDescriberCanAbort(t *testing.T) {
+	srv, runs := newConfirmedServer(t)
+	session := &elicitingSession{capabilities: elicitationCapable()}
+
+	result := callWithSession(t, srv, session, `{"confirm":true}`)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "missing name", resultText(t, result))
+	assert.Empty(t, session.requests)
+	assert.Empty(t, *runs)
+}
+
+func TestConfirmParameterIsAdvertised(t *testing.T) {
+	srv, _ := newConfirmedServer(t)
+	tool := srv.McpServer.GetTool("drop")
+	require.NotNil(t, tool)
+	assert.Contains(t, tool.Tool.InputSchema.Properties, confirmParameter)
+}
+
+func TestConfirmationDescribesValidatedArguments(t *testing.T) {
+	var described []int
+	srv := NewMcpServer("test", "1.0.0", WithToolConfirmation(true))
+	srv.AddTools(NewTool("drop_id", "", []ToolParameter{WithInteger("id", Required())},
+		func(ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+			return NewToolResultText("dropped"), nil
+		},
+		WithConfirmation(NewTypedConfirmation(
+			func(ctx context.Context, req CallToolRequest, args struct {
+				Id int `json:"id"`
+			}) (string, error) {
+				described = append(described, args.Id)
+				return fmt.Sprintf("Drop %d?", args.Id), nil
+			}))))
+
+	call := func(args string) *mcp.CallToolResult {
+		ctx := srv.McpServer.WithContext(context.Background(), &elicitingSession{})
+		return toolResult(t, srv.McpServer.HandleMessage(ctx, json.RawMessage(
+			`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"drop_id","arguments":`+args+`}}`)))
+	}
+
+	// Invalid arguments are rejected before the user is asked
+	result := call(`{"confirm":true}`)
+	assert.Equal(t, "Validation errors:\n- missing required parameter: id", resultText(t, result))
+	assert.Empty(t, described)
+
+	// The describer sees the coerced arguments, without confirm
+	result = call(`{"id":"12","confirm":true}`)
+	assert.Equal(t, "dropped", resultText(t, result))
+	assert.Equal(t, []int{12}, described)
+}
@@ -0,0 +1,81 @@
+package mcpgo
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ContextArgumentFunc derives the context a tool call runs with from the
+// value of a context argument, or nil when the argument is missing
+type ContextArgumentFunc func(ctx context.Context, value any) (context.Context, error)
+
+// contextArgument is an optional argument that is not passed to the tool
+// handler but changes the context it runs with, e.g. to pick a client
+type contextArgument struct {
+	param ToolParameter
+	apply ContextArgumentFunc
+}
+
+// WithContextArgument adds the parameter to the tool's schema and applies
+// its value to the context before anything else runs, including
+// confirmation. The handler never sees the argument.
+func WithContextArgument(param ToolParameter, apply ContextArgumentFunc) ToolOption {
+	return func(t *mark3labsToolImpl) {
+		// Copy so that customized copies of a tool do not share the slice
+		args := make([]contextArgument, 0, len(t.contextArguments)+1)
+		args = append(args, t.contextArguments...)
+		t.contextArguments = append(args, contextArgument{param: param, apply: apply})
+	}
+}
+
+// withContextArguments wraps a tool handler so that the context arguments
+// are validated, applied to the context and removed from the arguments
+func withContextArguments(
+	contextArgs []contextArgument,
+	handler server.ToolHandlerFunc,
+) server.ToolHandlerFunc {
+	if len(contextArgs) == 0 {
+		return handler
+	}
+
+	return func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+		remaining := make(map[string]any, len(args))
+		for name, value := range args {
+			remaining[name] = value
+		}
+
+		for _, contextArg := range contextArgs {
+			name := contextArg.param.Name
+			value, ok := remaining[name]
+			delete(remaining, name)
+
+			if ok && value != nil {
+				var errs []string
+				value, errs = validateValue(name, contextArg.param.jsonSchema(), value)
+				if len(errs) > 0 {
+					return mcp.NewToolResultError(
+						"Validation errors:\n- " + strings.Join(errs, "\n- ")), nil
+				}
+			} else {
+				value = nil
+			}
+
+			var err error
+			if ctx, err = contextArg.apply(ctx, value); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+
+		if req.Params.Arguments != nil {
+			req.Params.Arguments = remaining
+		}
+		return handler(ctx, req)
+	}
+}
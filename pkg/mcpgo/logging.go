@@ -0,0 +1,112 @@
+package mcpgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/log"
+)
+
+// clientLogger is a log.Logger that writes every entry to the wrapped
+// logger and also forwards it to the MCP client of the request as a
+// notifications/message. The client decides which entries it receives
+// through logging/setLevel.
+type clientLogger struct {
+	log.Logger
+	server *Mark3labsImpl
+}
+
+// NewClientLogger returns a logger that tees the entries written to next
+// to the client session found in the logging context. Entries logged
+// outside a client session are only written to next.
+func NewClientLogger(next log.Logger, mcpServer Server) (log.Logger, error) {
+	sImpl, ok := mcpServer.(*Mark3labsImpl)
+	if !ok {
+		return nil, fmt.Errorf("%w: expected *Mark3labsImpl, got %T",
+			ErrInvalidServerImplementation, mcpServer)
+	}
+
+	return &clientLogger{Logger: next, server: sImpl}, nil
+}
+
+// Infof logs an info message and forwards it to the client
+func (l *clientLogger) Infof(
+	ctx context.Context, format string, args ...interface{}) {
+	l.Logger.Infof(ctx, format, args...)
+	l.forward(ctx, mcp.LoggingLevelInfo, format, args)
+}
+
+// Errorf logs an error message and forwards it to the client
+func (l *clientLogger) Errorf(
+	ctx context.Context, format string, args ...interface{}) {
+	l.Logger.Errorf(ctx, format, args...)
+	l.forward(ctx, mcp.LoggingLevelError, format, args)
+}
+
+// Fatalf forwards a fatal message to the client before the wrapped
+// logger exits
+func (l *clientLogger) Fatalf(
+	ctx context.Context, format string, args ...interface{}) {
+	l.forward(ctx, mcp.LoggingLevelCritical, format, args)
+	l.Logger.Fatalf(ctx, format, args...)
+}
+
+// Debugf logs a debug message and forwards it to the client
+func (l *clientLogger) Debugf(
+	ctx context.Context, format string, args ...interface{}) {
+	l.Logger.Debugf(ctx, format, args...)
+	l.forward(ctx, mcp.LoggingLevelDebug, format, args)
+}
+
+// Warningf logs a warning message and forwards it to the client
+func (l *clientLogger) Warningf(
+	ctx context.Context, format string, args ...interface{}) {
+	l.Logger.Warningf(ctx, format, args...)
+	l.forward(ctx, mcp.LoggingLevelWarning, format, args)
+}
+
+// forward sends the entry to the client session in ctx. The server drops
+// entries below the level the client asked for, and sessions that are
+// missing, not yet initialized or unable to log are skipped silently.
+func (l *clientLogger) forward(
+	ctx context.Context,
+	level mcp.LoggingLevel,
+	format string,
+	args []interface{},
+) {
+	// Reporting a blocked notification channel through that same channel
+	// would only block again and log another failure
+	for _, arg := range args {
+		if err, ok := arg.(error); ok && errors.Is(err, server.ErrNotificationChannelBlocked) {
+			return
+		}
+	}
+
+	notification := mcp.NewLoggingMessageNotification(
+		level, l.server.Name, logData(format, args))
+	_ = l.server.McpServer.SendLogMessageToClient(ctx, notification)
+}
+
+// logData converts a log entry and its key-value pairs into the data of
+// a logging notification
+func logData(message string, args []interface{}) map[string]interface{} {
+	data := map[string]interface{}{"message": message}
+	for i := 0; i+1 < len(args); i += 2 {
+		key, ok := args[i].(string)
+		if !ok {
+			continue
+		}
+
+		// Errors have no exported fields and would encode as {}
+		if err, ok := args[i+1].(error); ok {
+			data[key] = err.Error()
+			continue
+		}
+		data[key] = args[i+1]
+	}
+	return data
+}
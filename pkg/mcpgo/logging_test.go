@@ -0,0 +1,73 @@
+package mcpgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loggingSession is a client session that asked for warnings and above
+type loggingSession struct {
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *loggingSession) Initialize()       {}
+func (s *loggingSession) Initialized() bool { return true }
+func (s *loggingSession) SessionID() string { return "test" }
+func (s *loggingSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+func (s *loggingSession) SetLogLevel(level mcp.LoggingLevel) {}
+func (s *loggingSession) GetLogLevel() mcp.LoggingLevel {
+	return mcp.LoggingLevelWarning
+}
+
+// recordingLogger remembers the messages written to it
+type recordingLogger struct {
+	messages []string
+}
+
+func (l *recordingLogger) record(format string) { l.messages = append(l.messages, format) }
+
+func (l *recordingLogger) Infof(_ context.Context, f string, _ ...interface{})    { l.record(f) }
+func (l *recordingLogger) Errorf(_ context.Context, f string, _ ...interface{})   { l.record(f) }
+func (l *recordingLogger) Fatalf(_ context.Context, f string, _ ...interface{})   { l.record(f) }
+func (l *recordingLogger) Debugf(_ context.Context, f string, _ ...interface{})   { l.record(f) }
+func (l *recordingLogger) Warningf(_ context.Context, f string, _ ...interface{}) { l.record(f) }
+func (l *recordingLogger) Close() error                                           { return nil }
+
+func TestClientLoggerForwardsEntriesAtClientLevel(t *testing.T) {
+	srv := NewMcpServer("test", "1.0.0", WithLogging())
+	session := &loggingSession{notifications: make(chan mcp.JSONRPCNotification, 10)}
+	ctx := srv.McpServer.WithContext(context.Background(), session)
+
+	next := &recordingLogger{}
+	logger, err := NewClientLogger(next, srv)
+	require.NoError(t, err)
+
+	logger.Infof(ctx, "TOOL_CALL_STARTED", "tool", "get_all_links")
+	logger.Warningf(ctx, "LINKWARDEN_REQUEST_FAILED",
+		"status", "401 Unauthorized",
+		"error", errors.New("unauthorized"))
+	logger.Errorf(context.Background(), "NO_SESSION")
+
+	assert.Equal(t, []string{
+		"TOOL_CALL_STARTED",
+		"LINKWARDEN_REQUEST_FAILED",
+		"NO_SESSION",
+	}, next.messages)
+
+	require.Len(t, session.notifications, 1)
+	notification := <-session.notifications
+	assert.Equal(t, "notifications/message", notification.Method)
+	assert.Equal(t, mcp.LoggingLevelWarning, notification.Params.AdditionalFields["level"])
+	assert.Equal(t, map[string]interface{}{
+		"message": "LINKWARDEN_REQUEST_FAILED",
+		"status":  "401 Unauthorized",
+		"error":   "unauthorized",
+	}, notification.Params.AdditionalFields["data"])
+}
@@ -0,0 +1,136 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/observability"
+)
+
+// ToolMiddleware wraps the handler of a tool with cross-cutting behavior.
+// It receives the tool so it can act on its name and annotations.
+type ToolMiddleware func(tool Tool, next ToolHandler) ToolHandler
+
+// toolMiddlewares holds the middlewares applied to every tool, outermost
+// first
+type toolMiddlewares []ToolMiddleware
+
+// WithMiddleware returns a server option that wraps the handler of every
+// tool with the given middlewares. The first middleware is the outermost,
+// and options given later wrap inside earlier ones.
+func WithMiddleware(middlewares ...ToolMiddleware) ServerOption {
+	return func(s OptionSetter) error {
+		return s.SetOption(toolMiddlewares(middlewares))
+	}
+}
+
+// chain wraps handler with the middlewares, outermost first
+func (m toolMiddlewares) chain(tool Tool, handler ToolHandler) ToolHandler {
+	for i := len(m) - 1; i >= 0; i-- {
+		handler = m[i](tool, handler)
+	}
+	return handler
+}
+
+// LoggingMiddleware logs the start and the result of every tool call
+func LoggingMiddleware(obs *observability.Observability) ToolMiddleware {
+	return func(tool Tool, next ToolHandler) ToolHandler {
+		return func(ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+			obs.Logger.Infof(ctx, "TOOL_CALL_STARTED",
+				"tool", req.Name,
+				"arguments", req.Arguments)
+
+			result, err := next(ctx, req)
+			if err != nil {
+				obs.Logger.Errorf(ctx, "TOOL_CALL_FAILED",
+					"tool", req.Name,
+					"error", err)
+				return result, err
+			}
+
+			obs.Logger.Infof(ctx, "TOOL_CALL_COMPLETED",
+				"tool", req.Name,
+				"result", result)
+			return result, nil
+		}
+	}
+}
+
+// RecoveryMiddleware turns a panicking tool call into an error result
+// instead of crashing the server
+func RecoveryMiddleware(obs *observability.Observability) ToolMiddleware {
+	return func(tool Tool, next ToolHandler) ToolHandler {
+		return func(ctx context.Context, req CallToolRequest) (result *ToolResult, err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					obs.Logger.Errorf(ctx, "TOOL_CALL_PANICKED",
+						"tool", req.Name,
+						"panic", r,
+						"stack", string(debug.Stack()))
+					result = NewToolResultError(fmt.Sprintf(
+						"Tool %s failed unexpectedly", req.Name))
+					err = nil
+				}
+			}()
+
+			return next(ctx, req)
+		}
+	}
+}
+
+// TimingMiddleware logs how long every tool call took
+func TimingMiddleware(obs *observability.Observability) ToolMiddleware {
+	return func(tool Tool, next ToolHandler) ToolHandler {
+		return func(ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+			start := time.Now()
+			result, err := next(ctx, req)
+
+			obs.Logger.Infof(ctx, "TOOL_CALL_DURATION",
+				"tool", req.Name,
+				"duration_ms", time.Since(start).Milliseconds(),
+				"is_error", err != nil || (result != nil && result.IsError))
+			return result, err
+		}
+	}
+}
+
+// ReadOnlyMiddleware rejects calls to tools that are not annotated as
+// read-only while readOnly reports true. It is asked on every call, so
+// read-only mode can change while the server runs.
+func ReadOnlyMiddleware(readOnly func() bool) ToolMiddleware {
+	return func(tool Tool, next ToolHandler) ToolHandler {
+		return func(ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+			if !readOnly() {
+				return next(ctx, req)
+			}
+
+			readOnlyHint := tool.GetAnnotations().ReadOnlyHint
+			if readOnlyHint == nil || !*readOnlyHint {
+				return NewToolResultError(fmt.Sprintf(
+					"Tool %s is not available in read-only mode", req.Name)), nil
+			}
+			return next(ctx, req)
+		}
+	}
+}
+
+// ErrorNormalizationMiddleware reports handler errors and missing results
+// as tool error results, so the model sees them instead of a protocol error
+func ErrorNormalizationMiddleware() ToolMiddleware {
+	return func(tool Tool, next ToolHandler) ToolHandler {
+		return func(ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+			result, err := next(ctx, req)
+			if err != nil {
+				return NewToolResultError(fmt.Sprintf(
+					"Tool %s failed: %s", req.Name, err.Error())), nil
+			}
+			if result == nil {
+				return NewToolResultError(fmt.Sprintf(
+					"Tool %s returned no result", req.Name)), nil
+			}
+			return result, nil
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package mcpgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/log"
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/observability"
+)
+
+func TestMiddlewaresWrapOutermostFirst(t *testing.T) {
+	calls := []string{}
+	record := func(name string) ToolMiddleware {
+		return func(tool Tool, next ToolHandler) ToolHandler {
+			return func(ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+				calls = append(calls, name)
+				return next(ctx, req)
+			}
+		}
+	}
+
+	tool := NewTool("test", "", nil,
+		func(ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+			calls = append(calls, "handler")
+			return NewToolResultText("ok"), nil
+		})
+
+	handler := toolMiddlewares{record("outer"), record("inner")}.chain(tool, tool.GetHandler())
+	_, err := handler(context.Background(), CallToolRequest{Name: "test"})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"outer", "inner", "handler"}, calls)
+}
+
+func TestRecoveryAndErrorNormalization(t *testing.T) {
+	logger, err := log.NewSlogger()
+	require.NoError(t, err)
+	obs := observability.New(observability.WithLogging(logger))
+
+	middlewares := toolMiddlewares{ErrorNormalizationMiddleware(), RecoveryMiddleware(obs)}
+	req := CallToolRequest{Name: "test"}
+
+	panicking := NewTool("test", "", nil,
+		func(ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+			panic("boom")
+		})
+	result, err := middlewares.chain(panicking, panicking.GetHandler())(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "Tool test failed unexpectedly", result.Text)
+
+	failing := NewTool("test", "", nil,
+		func(ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+			return nil, errors.New("no connection")
+		})
+	result, err = middlewares.chain(failing, failing.GetHandler())(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	assert.Equal(t, "Tool test failed: no connection", result.Text)
+}
+
+func TestReadOnlyMiddlewareRejectsWriteTools(t *testing.T) {
+	handler := func(ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+		return NewToolResultText("ok"), nil
+	}
+	readOnly := true
+	middlewares := toolMiddlewares{ReadOnlyMiddleware(func() bool { return readOnly })}
+
+	read := NewTool("read", "", nil, handler, WithReadOnlyHint(true))
+	result, err := middlewares.chain(read, handler)(context.Background(), CallToolRequest{Name: "read"})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+
+	write := NewTool("write", "", nil, handler)
+	result, err = middlewares.chain(write, handler)(context.Background(), CallToolRequest{Name: "write"})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+
+	readOnly = false
+	result, err = middlewares.chain(write, handler)(context.Background(), CallToolRequest{Name: "write"})
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+}
@@ -0,0 +1,60 @@
+package mcpgo
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// methodNotificationProgress is the MCP method for progress notifications
+const methodNotificationProgress = "notifications/progress"
+
+// ProgressReporter reports the progress of a long-running tool call
+type ProgressReporter interface {
+	// Report sends the progress made so far. Total is the amount of work
+	// when known, or 0 otherwise. Reporting is best effort and does
+	// nothing when the client did not ask for progress.
+	Report(ctx context.Context, progress, total float64, message string)
+}
+
+// mark3labsProgressReporter implements the ProgressReporter interface by
+// sending notifications/progress for the request's progress token
+type mark3labsProgressReporter struct {
+	token mcp.ProgressToken
+}
+
+// newProgressReporter creates a progress reporter for the given request
+func newProgressReporter(req mcp.CallToolRequest) ProgressReporter {
+	reporter := &mark3labsProgressReporter{}
+	if req.Params.Meta != nil {
+		reporter.token = req.Params.Meta.ProgressToken
+	}
+	return reporter
+}
+
+// Report implements the ProgressReporter interface
+func (r *mark3labsProgressReporter) Report(
+	ctx context.Context, progress, total float64, message string) {
+	if r.token == nil {
+		return
+	}
+
+	mcpServer := server.ServerFromContext(ctx)
+	if mcpServer == nil {
+		return
+	}
+
+	params := map[string]any{
+		"progressToken": r.token,
+		"progress":      progress,
+	}
+	if total > 0 {
+		params["total"] = total
+	}
+	if message != "" {
+		params["message"] = message
+	}
+
+	_ = mcpServer.SendNotificationToClient(ctx, methodNotificationProgress, params)
+}
@@ -0,0 +1,43 @@
+package mcpgo
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressIsSentOnlyForProgressTokens(t *testing.T) {
+	srv := NewMcpServer("test", "1.0.0")
+	srv.AddTools(NewTool("work", "", nil,
+		func(ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+			req.ReportProgress(ctx, 1, 2, "halfway")
+			req.ReportProgress(ctx, 2, 2, "")
+			return NewToolResultText("done"), nil
+		}))
+
+	session := &loggingSession{notifications: make(chan mcp.JSONRPCNotification, 10)}
+	ctx := srv.McpServer.WithContext(context.Background(), session)
+
+	srv.McpServer.HandleMessage(ctx, json.RawMessage(
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"work"}}`))
+	assert.Empty(t, session.notifications)
+
+	srv.McpServer.HandleMessage(ctx, json.RawMessage(
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"work","_meta":{"progressToken":"p1"}}}`))
+	assert.Len(t, session.notifications, 2)
+
+	first := <-session.notifications
+	assert.Equal(t, "notifications/progress", first.Method)
+	assert.Equal(t, map[string]any{
+		"progressToken": "p1",
+		"progress":      float64(1),
+		"total":         float64(2),
+		"message":       "halfway",
+	}, first.Params.AdditionalFields)
+
+	second := <-session.notifications
+	assert.NotContains(t, second.Params.AdditionalFields, "message")
+}
@@ -0,0 +1,213 @@
+package mcpgo
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// PromptHandler handles prompt requests
+type PromptHandler func(
+	ctx context.Context,
+	request GetPromptRequest) (*PromptResult, error)
+
+// GetPromptRequest represents a request to get a prompt
+type GetPromptRequest struct {
+	Name      string
+	Arguments map[string]string
+}
+
+// PromptRole is the speaker of a prompt message
+type PromptRole string
+
+const (
+	// PromptRoleUser marks a message written as the user
+	PromptRoleUser PromptRole = "user"
+	// PromptRoleAssistant marks a message written as the assistant
+	PromptRoleAssistant PromptRole = "assistant"
+)
+
+// PromptMessage represents a single text message of a prompt
+type PromptMessage struct {
+	Role PromptRole
+	Text string
+}
+
+// PromptResult represents the result of a prompt request
+type PromptResult struct {
+	Description string
+	Messages    []PromptMessage
+}
+
+// Prompt represents a prompt that can be added to the server
+type Prompt interface {
+	// internal method to convert to mcp's ServerPrompt
+	toMCPServerPrompt() server.ServerPrompt
+
+	// GetName returns the name of the prompt
+	GetName() string
+
+	// GetHandler returns the underlying handler
+	GetHandler() PromptHandler
+
+	// GetCompleters returns the argument completers keyed by argument name
+	GetCompleters() map[string]ArgumentCompleter
+}
+
+// PromptArgument represents an argument of a prompt
+type PromptArgument struct {
+	Name        string
+	Description string
+	Required    bool
+	Completer   ArgumentCompleter
+}
+
+// ArgumentOption represents a customization option for a prompt argument
+type ArgumentOption func(arg *PromptArgument)
+
+// ArgumentDescription sets the description of the prompt argument
+func ArgumentDescription(desc string) ArgumentOption {
+	return func(arg *PromptArgument) {
+		arg.Description = desc
+	}
+}
+
+// RequiredArgument marks the prompt argument as required
+func RequiredArgument() ArgumentOption {
+	return func(arg *PromptArgument) {
+		arg.Required = true
+	}
+}
+
+// CompleteWith registers a completer that suggests values for the
+// prompt argument
+func CompleteWith(completer ArgumentCompleter) ArgumentOption {
+	return func(arg *PromptArgument) {
+		arg.Completer = completer
+	}
+}
+
+// WithArgument creates a prompt argument with optional argument options
+func WithArgument(name string, opts ...ArgumentOption) PromptArgument {
+	arg := PromptArgument{Name: name}
+	for _, opt := range opts {
+		opt(&arg)
+	}
+	return arg
+}
+
+// mark3labsPromptImpl implements the Prompt interface
+type mark3labsPromptImpl struct {
+	name        string
+	description string
+	handler     PromptHandler
+	arguments   []PromptArgument
+}
+
+// NewPrompt creates a new prompt with the given
+// name, description, arguments and handler
+func NewPrompt(
+	name,
+	description string,
+	arguments []PromptArgument,
+	handler PromptHandler) *mark3labsPromptImpl {
+	return &mark3labsPromptImpl{
+		name:        name,
+		description: description,
+		handler:     handler,
+		arguments:   arguments,
+	}
+}
+
+// GetName returns the name of the prompt
+func (p *mark3labsPromptImpl) GetName() string {
+	return p.name
+}
+
+// GetHandler returns the handler for the prompt
+func (p *mark3labsPromptImpl) GetHandler() PromptHandler {
+	return p.handler
+}
+
+// GetCompleters returns the argument completers of the prompt
+func (p *mark3labsPromptImpl) GetCompleters() map[string]ArgumentCompleter {
+	completers := make(map[string]ArgumentCompleter)
+	for _, arg := range p.arguments {
+		if arg.Completer != nil {
+			completers[arg.Name] = arg.Completer
+		}
+	}
+	return completers
+}
+
+// toMCPServerPrompt converts our Prompt to mcp's ServerPrompt
+func (p *mark3labsPromptImpl) toMCPServerPrompt() server.ServerPrompt {
+	promptOpts := []mcp.PromptOption{
+		mcp.WithPromptDescription(p.description),
+	}
+
+	for _, arg := range p.arguments {
+		argOpts := []mcp.ArgumentOption{
+			mcp.ArgumentDescription(arg.Description),
+		}
+		if arg.Required {
+			argOpts = append(argOpts, mcp.RequiredArgument())
+		}
+		promptOpts = append(promptOpts, mcp.WithArgument(arg.Name, argOpts...))
+	}
+
+	prompt := mcp.NewPrompt(p.name, promptOpts...)
+
+	handlerFunc := func(
+		ctx context.Context,
+		req mcp.GetPromptRequest,
+	) (*mcp.GetPromptResult, error) {
+		// Convert mcp request to our request
+		ourReq := GetPromptRequest{
+			Name:      req.Params.Name,
+			Arguments: req.Params.Arguments,
+		}
+
+		// Call our handler
+		result, err := p.handler(ctx, ourReq)
+		if err != nil {
+			return nil, err
+		}
+
+		// Convert our result to mcp result
+		messages := make([]mcp.PromptMessage, 0, len(result.Messages))
+		for _, msg := range result.Messages {
+			messages = append(messages, mcp.NewPromptMessage(
+				mcp.Role(msg.Role),
+				mcp.NewTextContent(msg.Text),
+			))
+		}
+
+		return mcp.NewGetPromptResult(result.Description, messages), nil
+	}
+
+	return server.ServerPrompt{
+		Prompt:  prompt,
+		Handler: handlerFunc,
+	}
+}
+
+// NewPromptResult creates a new prompt result with the given messages
+func NewPromptResult(
+	description string, messages ...PromptMessage) *PromptResult {
+	return &PromptResult{
+		Description: description,
+		Messages:    messages,
+	}
+}
+
+// NewUserMessage creates a prompt message written as the user
+func NewUserMessage(text string) PromptMessage {
+	return PromptMessage{Role: PromptRoleUser, Text: text}
+}
+
+// NewAssistantMessage creates a prompt message written as the assistant
+func NewAssistantMessage(text string) PromptMessage {
+	return PromptMessage{Role: PromptRoleAssistant, Text: text}
+}
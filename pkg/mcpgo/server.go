@@ -13,6 +13,12 @@ import (
 type Server interface {
 	// AddTools adds tools to the server
 	AddTools(tools ...Tool)
+
+	// SetTools replaces every tool of the server, notifying clients once
+	SetTools(tools ...Tool)
+
+	// AddPrompts adds prompts to the server
+	AddPrompts(prompts ...Prompt)
 }
 
 // NewMcpServer creates a new MCP server
@@ -27,6 +33,20 @@ func NewMcpServer(name, version string, opts ...ServerOption) *Mark3labsImpl {
 		_ = opt(optSetter)
 	}
 
+	// Record request IDs of tool calls so they can be cancelled
+	hooks := optSetter.hooks
+	if hooks == nil {
+		hooks = &server.Hooks{}
+	}
+	hooks.AddBeforeCallTool(tagRequestID)
+	optSetter.mcpOptions = append(optSetter.mcpOptions, server.WithHooks(hooks))
+
+	// Prompt arguments are completed from the completers of the prompts
+	completions := newCompletionRegistry()
+	optSetter.mcpOptions = append(optSetter.mcpOptions,
+		server.WithCompletions(),
+		server.WithPromptCompletionProvider(completions))
+
 	// Create the underlying mcp server
 	mcpServer := server.NewMCPServer(
 		name,
@@ -34,11 +54,20 @@ func NewMcpServer(name, version string, opts ...ServerOption) *Mark3labsImpl {
 		optSetter.mcpOptions...,
 	)
 
-	return &Mark3labsImpl{
-		McpServer: mcpServer,
-		Name:      name,
-		Version:   version,
+	impl := &Mark3labsImpl{
+		McpServer:    mcpServer,
+		Name:         name,
+		Version:      version,
+		toolTimeouts: optSetter.toolTimeouts,
+		confirmation: optSetter.confirmation,
+		middlewares:  optSetter.middlewares,
+		inflight:     newInflightRequests(),
+		completions:  completions,
 	}
+
+	mcpServer.AddNotificationHandler(methodNotificationCancelled, impl.handleCancelled)
+
+	return impl
 }
 
 // Mark3labsImpl implements the Server interface using mark3labs/mcp-go
@@ -46,28 +75,89 @@ type Mark3labsImpl struct {
 	McpServer *server.MCPServer
 	Name      string
 	Version   string
+
+	toolTimeouts toolTimeouts
+	confirmation confirmationPolicy
+	middlewares  toolMiddlewares
+	inflight     *inflightRequests
+	completions  *completionRegistry
 }
 
 // mark3labsOptionSetter is used to apply options to the server
 type mark3labsOptionSetter struct {
-	mcpOptions []server.ServerOption
+	mcpOptions   []server.ServerOption
+	hooks        *server.Hooks
+	toolTimeouts toolTimeouts
+	confirmation confirmationPolicy
+	middlewares  toolMiddlewares
 }
 
 func (s *mark3labsOptionSetter) SetOption(option interface{}) error {
-	if opt, ok := option.(server.ServerOption); ok {
+	switch opt := option.(type) {
+	case server.ServerOption:
 		s.mcpOptions = append(s.mcpOptions, opt)
+	case *server.Hooks:
+		s.hooks = opt
+	case toolTimeouts:
+		s.toolTimeouts = opt
+	case confirmationPolicy:
+		s.confirmation = opt
+	case toolMiddlewares:
+		s.middlewares = append(s.middlewares, opt...)
 	}
 	return nil
 }
 
 // AddTools adds tools to the server
 func (s *Mark3labsImpl) AddTools(tools ...Tool) {
-	// Convert our Tool to mcp's ServerTool
+	s.McpServer.AddTools(s.serverTools(tools)...)
+}
+
+// SetTools replaces every tool of the server
+func (s *Mark3labsImpl) SetTools(tools ...Tool) {
+	s.McpServer.SetTools(s.serverTools(tools)...)
+}
+
+// serverTools converts our tools to mcp's ServerTools
+func (s *Mark3labsImpl) serverTools(tools []Tool) []server.ServerTool {
 	var mcpTools []server.ServerTool
 	for _, tool := range tools {
-		mcpTools = append(mcpTools, tool.toMCPServerTool())
+		describe := tool.GetConfirmation()
+		confirm := describe != nil && s.confirmation.enabled
+
+		// Confirmation runs after argument validation, so the user is
+		// not asked about a call that would be rejected anyway, and
+		// before the timeout starts
+		var inner toolMiddlewares
+		if confirm {
+			inner = append(inner, withConfirmation(describe))
+		}
+		inner = append(inner, withTimeout(s.toolTimeouts.timeoutFor(tool)))
+
+		mcpTool := tool.toMCPServerTool(s.middlewares, inner)
+		if confirm {
+			addConfirmParameter(&mcpTool.Tool)
+			mcpTool.Handler = withConfirmArgument(mcpTool.Handler)
+		}
+		mcpTool.Handler = withContextArguments(tool.getContextArguments(), mcpTool.Handler)
+		mcpTool.Handler = s.withCancellation(tool.GetName(), mcpTool.Handler)
+		mcpTools = append(mcpTools, mcpTool)
 	}
-	s.McpServer.AddTools(mcpTools...)
+	return mcpTools
+}
+
+// AddPrompts adds prompts to the server
+func (s *Mark3labsImpl) AddPrompts(prompts ...Prompt) {
+	// Convert our Prompt to mcp's ServerPrompt
+	var mcpPrompts []server.ServerPrompt
+	for _, prompt := range prompts {
+		mcpPrompts = append(mcpPrompts, prompt.toMCPServerPrompt())
+
+		for arg, completer := range prompt.GetCompleters() {
+			s.completions.add(prompt.GetName(), arg, completer)
+		}
+	}
+	s.McpServer.AddPrompts(mcpPrompts...)
 }
 
 // OptionSetter is an interface for setting options on a configurable object
@@ -85,9 +175,11 @@ func WithLogging() ServerOption {
 	}
 }
 
+// WithHooks returns a server option that registers the given hooks.
+// The server adds its own hooks to the same set.
 func WithHooks(hooks *server.Hooks) ServerOption {
 	return func(s OptionSetter) error {
-		return s.SetOption(server.WithHooks(hooks))
+		return s.SetOption(hooks)
 	}
 }
 
@@ -99,6 +191,14 @@ func WithResourceCapabilities(read, list bool) ServerOption {
 	}
 }
 
+// WithPromptCapabilities returns a server option that enables prompt
+// capabilities
+func WithPromptCapabilities(listChanged bool) ServerOption {
+	return func(s OptionSetter) error {
+		return s.SetOption(server.WithPromptCapabilities(listChanged))
+	}
+}
+
 // WithToolCapabilities returns a server option that enables tool capabilities
 func WithToolCapabilities(enabled bool) ServerOption {
 	return func(s OptionSetter) error {
@@ -148,20 +248,5 @@ func SetupHooks(obs *observability.Observability) *server.Hooks {
 			"error", err)
 	})
 
-	hooks.AddBeforeCallTool(func(ctx context.Context, id any,
-		message *mcp.CallToolRequest) {
-		obs.Logger.Infof(ctx, "TOOL_CALL_STARTED",
-			"id", id,
-			"request", message)
-	})
-
-	hooks.AddAfterCallTool(func(ctx context.Context, id any,
-		message *mcp.CallToolRequest, result *mcp.CallToolResult) {
-		obs.Logger.Infof(ctx, "TOOL_CALL_COMPLETED",
-			"id", id,
-			"request", message,
-			"result", result)
-	})
-
 	return hooks
 }
@@ -3,6 +3,7 @@ package mcpgo
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -17,6 +18,18 @@ type ToolHandler func(
 type CallToolRequest struct {
 	Name      string
 	Arguments any
+	// Progress reports the progress of the call back to the client
+	Progress ProgressReporter
+}
+
+// ReportProgress reports the progress of the call when the request has a
+// progress reporter attached
+func (r CallToolRequest) ReportProgress(
+	ctx context.Context, progress, total float64, message string) {
+	if r.Progress == nil {
+		return
+	}
+	r.Progress.Report(ctx, progress, total, message)
 }
 
 // ToolResult represents the result of a tool call
@@ -28,11 +41,160 @@ type ToolResult struct {
 
 // Tool represents a tool that can be added to the server
 type Tool interface {
-	// internal method to convert to mcp's ServerTool
-	toMCPServerTool() server.ServerTool
+	// internal method to convert to mcp's ServerTool, wrapping the
+	// handler with the outer middlewares around argument validation and
+	// the inner middlewares within it
+	toMCPServerTool(outer, inner toolMiddlewares) server.ServerTool
+
+	// GetName returns the name clients call the tool by
+	GetName() string
+
+	// GetDescription returns the description of the tool
+	GetDescription() string
+
+	// GetParameters returns the top-level parameters of the tool
+	GetParameters() []ToolParameter
+
+	// Customize returns a copy of the tool with the options applied,
+	// leaving the tool itself unchanged
+	Customize(opts ...ToolOption) Tool
+
+	// internal method returning the name the tool was created with,
+	// before any prefix
+	baseName() string
 
 	// GetHandler internal method for fetching the underlying handler
 	GetHandler() ToolHandler
+
+	// GetAnnotations returns the behavior hints of the tool
+	GetAnnotations() ToolAnnotations
+
+	// SetDefaultAnnotations fills in the hints the tool did not set itself
+	SetDefaultAnnotations(defaults ToolAnnotations)
+
+	// GetTimeout returns the tool's own timeout, or 0 to use the default
+	GetTimeout() time.Duration
+
+	// GetConfirmation returns how to describe a call for confirmation,
+	// or nil if the tool runs without it
+	GetConfirmation() ConfirmationDescriber
+
+	// internal method returning the arguments applied to the context
+	// instead of being passed to the handler
+	getContextArguments() []contextArgument
+}
+
+// ToolAnnotations describes the behavior of a tool to clients.
+// Hints left nil fall back to the MCP defaults.
+type ToolAnnotations struct {
+	Title           string
+	ReadOnlyHint    *bool
+	DestructiveHint *bool
+	IdempotentHint  *bool
+	OpenWorldHint   *bool
+}
+
+// withDefaults returns the annotations with unset fields taken from defaults
+func (a ToolAnnotations) withDefaults(defaults ToolAnnotations) ToolAnnotations {
+	if a.Title == "" {
+		a.Title = defaults.Title
+	}
+	if a.ReadOnlyHint == nil {
+		a.ReadOnlyHint = defaults.ReadOnlyHint
+	}
+	if a.DestructiveHint == nil {
+		a.DestructiveHint = defaults.DestructiveHint
+	}
+	if a.IdempotentHint == nil {
+		a.IdempotentHint = defaults.IdempotentHint
+	}
+	if a.OpenWorldHint == nil {
+		a.OpenWorldHint = defaults.OpenWorldHint
+	}
+	return a
+}
+
+// ToolOption represents a customization option for a tool
+type ToolOption func(t *mark3labsToolImpl)
+
+// WithTimeout sets how long a call to the tool may run. It replaces the
+// server's default tool timeout, but not a configured per-tool override.
+func WithTimeout(timeout time.Duration) ToolOption {
+	return func(t *mark3labsToolImpl) {
+		t.timeout = timeout
+	}
+}
+
+// WithConfirmation marks the tool as one the user should confirm before it
+// runs, using describe to tell them what the call will do
+func WithConfirmation(describe ConfirmationDescriber) ToolOption {
+	return func(t *mark3labsToolImpl) {
+		t.confirmation = describe
+	}
+}
+
+// WithNamePrefix prepends prefix to the name clients call the tool by,
+// which keeps tools apart from those of other servers
+func WithNamePrefix(prefix string) ToolOption {
+	return func(t *mark3labsToolImpl) {
+		t.name = prefix + t.originalName
+	}
+}
+
+// WithDescription replaces the description of the tool
+func WithDescription(description string) ToolOption {
+	return func(t *mark3labsToolImpl) {
+		t.description = description
+	}
+}
+
+// WithParameterDescription replaces the description of the top-level
+// parameter with the given name
+func WithParameterDescription(param, description string) ToolOption {
+	return func(t *mark3labsToolImpl) {
+		for i := range t.parameters {
+			if t.parameters[i].Name == param {
+				t.parameters[i].Schema["description"] = description
+			}
+		}
+	}
+}
+
+// WithTitle sets a human-readable title for the tool
+func WithTitle(title string) ToolOption {
+	return func(t *mark3labsToolImpl) {
+		t.annotations.Title = title
+	}
+}
+
+// WithReadOnlyHint marks whether the tool leaves its environment unchanged
+func WithReadOnlyHint(value bool) ToolOption {
+	return func(t *mark3labsToolImpl) {
+		t.annotations.ReadOnlyHint = &value
+	}
+}
+
+// WithDestructiveHint marks whether the tool may delete or overwrite data
+func WithDestructiveHint(value bool) ToolOption {
+	return func(t *mark3labsToolImpl) {
+		t.annotations.DestructiveHint = &value
+	}
+}
+
+// WithIdempotentHint marks whether repeated calls with the same arguments
+// have no additional effect
+func WithIdempotentHint(value bool) ToolOption {
+	return func(t *mark3labsToolImpl) {
+		t.annotations.IdempotentHint = &value
+	}
+}
+
+// WithOpenWorldHint marks whether the tool interacts with entities outside
+// of the Linkwarden instance
+func WithOpenWorldHint(value bool) ToolOption {
+	return func(t *mark3labsToolImpl) {
+		t.annotations.OpenWorldHint = &value
+	}
 }
 
 // PropertyOption represents a customization option for
@@ -144,12 +306,73 @@ func Description(desc string) PropertyOption {
 	}
 }
 
+// Items sets the schema for the elements of an array parameter.
+// The item is built with the same helpers as parameters, e.g.
+// Items(WithString("")), and its name is ignored.
+func Items(item ToolParameter) PropertyOption {
+	return func(schema map[string]interface{}) {
+		propType, ok := schema["type"].(string)
+		if !ok || propType != "array" {
+			return
+		}
+		schema["items"] = item.jsonSchema()
+	}
+}
+
+// Properties sets the nested properties of an object parameter.
+// Nested properties marked as Required() are listed in the object's
+// required list.
+func Properties(props ...ToolParameter) PropertyOption {
+	return func(schema map[string]interface{}) {
+		propType, ok := schema["type"].(string)
+		if !ok || propType != "object" {
+			return
+		}
+
+		properties := make(map[string]interface{}, len(props))
+		var required []string
+		for _, prop := range props {
+			properties[prop.Name] = prop.jsonSchema()
+			if isRequired, ok := prop.Schema["required"].(bool); ok && isRequired {
+				required = append(required, prop.Name)
+			}
+		}
+
+		schema["properties"] = properties
+		if len(required) > 0 {
+			schema[requiredPropertiesKey] = required
+		}
+	}
+}
+
+// requiredPropertiesKey holds the required nested properties of an object
+// parameter, since "required" marks the parameter itself as required
+const requiredPropertiesKey = "requiredProperties"
+
 // ToolParameter represents a parameter for a tool
 type ToolParameter struct {
 	Name   string
 	Schema map[string]interface{}
 }
 
+// jsonSchema returns the parameter schema as a plain JSON schema,
+// suitable for nesting inside an array or object parameter
+func (p ToolParameter) jsonSchema() map[string]interface{} {
+	schema := make(map[string]interface{}, len(p.Schema))
+	for k, v := range p.Schema {
+		switch k {
+		case "required":
+			// Required nested properties are listed on the parent object
+			continue
+		case requiredPropertiesKey:
+			schema["required"] = v
+		default:
+			schema[k] = v
+		}
+	}
+	return schema
+}
+
 // applyPropertyOptions applies the given property options to
 // the parameter schema
 func (p *ToolParameter) applyPropertyOptions(opts ...PropertyOption) {
@@ -178,6 +401,16 @@ func WithNumber(name string, opts ...PropertyOption) ToolParameter {
 	return param
 }
 
+// WithInteger creates an integer parameter with optional property options
+func WithInteger(name string, opts ...PropertyOption) ToolParameter {
+	param := ToolParameter{
+		Name:   name,
+		Schema: map[string]interface{}{"type": "integer"},
+	}
+	param.applyPropertyOptions(opts...)
+	return param
+}
+
 // WithBoolean creates a boolean parameter with optional property options
 func WithBoolean(name string, opts ...PropertyOption) ToolParameter {
 	param := ToolParameter{
@@ -210,25 +443,39 @@ func WithArray(name string, opts ...PropertyOption) ToolParameter {
 
 // mark3labsToolImpl implements the Tool interface
 type mark3labsToolImpl struct {
-	name        string
-	description string
-	handler     ToolHandler
-	parameters  []ToolParameter
+	name         string
+	originalName string
+	description  string
+	handler      ToolHandler
+	parameters   []ToolParameter
+	annotations  ToolAnnotations
+	timeout      time.Duration
+	confirmation ConfirmationDescriber
+
+	contextArguments []contextArgument
 }
 
 // NewTool creates a new tool with the given
-// Name, description, parameters and handler
+// Name, description, parameters, handler and optional tool options
 func NewTool(
 	name,
 	description string,
 	parameters []ToolParameter,
-	handler ToolHandler) *mark3labsToolImpl {
-	return &mark3labsToolImpl{
-		name:        name,
-		description: description,
-		handler:     handler,
-		parameters:  parameters,
+	handler ToolHandler,
+	opts ...ToolOption) *mark3labsToolImpl {
+	tool := &mark3labsToolImpl{
+		name:         name,
+		originalName: name,
+		description:  description,
+		handler:      handler,
+		parameters:   parameters,
+	}
+
+	for _, opt := range opts {
+		opt(tool)
 	}
+
+	return tool
 }
 
 // addNumberPropertyOptions adds number-specific options to the property options
@@ -279,6 +526,8 @@ func addDefaultValueOptions(
 		propOpts = append(propOpts, mcp.DefaultString(val))
 	case float64:
 		propOpts = append(propOpts, mcp.DefaultNumber(val))
+	case int:
+		propOpts = append(propOpts, mcp.DefaultNumber(float64(val)))
 	case bool:
 		propOpts = append(propOpts, mcp.DefaultBool(val))
 	}
@@ -343,6 +592,55 @@ func addArrayPropertyOptions(
 	return propOpts
 }
 
+// addNestedSchemaOptions adds item and property schemas of arrays
+// and objects
+func addNestedSchemaOptions(
+	propOpts []mcp.PropertyOption,
+	schema map[string]interface{}) []mcp.PropertyOption {
+	// Add items if present
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		propOpts = append(propOpts, mcp.Items(items))
+	}
+
+	// Add properties if present
+	if props, ok := schema["properties"].(map[string]interface{}); ok {
+		propOpts = append(propOpts, mcp.Properties(props))
+	}
+
+	return propOpts
+}
+
+// withInteger adds an integer property like mcp.WithNumber, since mcp
+// has no integer helper and would otherwise advertise a plain number
+func withInteger(name string, opts ...mcp.PropertyOption) mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		mcp.WithNumber(name, opts...)(t)
+
+		if prop, ok := t.InputSchema.Properties[name].(map[string]any); ok {
+			prop["type"] = "integer"
+		}
+	}
+}
+
+// withObject adds an object property like mcp.WithObject and also lists
+// its required nested properties, which mcp has no property option for
+func withObject(
+	name string,
+	schema map[string]interface{},
+	opts ...mcp.PropertyOption) mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		mcp.WithObject(name, opts...)(t)
+
+		required, ok := schema[requiredPropertiesKey].([]string)
+		if !ok {
+			return
+		}
+		if prop, ok := t.InputSchema.Properties[name].(map[string]any); ok {
+			prop["required"] = required
+		}
+	}
+}
+
 // convertSchemaToPropertyOptions converts our schema to mcp property options
 func convertSchemaToPropertyOptions(
 	schema map[string]interface{}) []mcp.PropertyOption {
@@ -378,27 +676,131 @@ func convertSchemaToPropertyOptions(
 			propOpts = addObjectPropertyOptions(propOpts, schema)
 		case "minItems", "maxItems":
 			propOpts = addArrayPropertyOptions(propOpts, schema)
+		case "items", "properties":
+			propOpts = addNestedSchemaOptions(propOpts, schema)
 		}
 	}
 
 	return propOpts
 }
 
+// GetName returns the name of the tool
+func (t *mark3labsToolImpl) GetName() string {
+	return t.name
+}
+
+// GetDescription returns the description of the tool
+func (t *mark3labsToolImpl) GetDescription() string {
+	return t.description
+}
+
+// GetParameters returns the top-level parameters of the tool
+func (t *mark3labsToolImpl) GetParameters() []ToolParameter {
+	return t.parameters
+}
+
+// Customize returns a copy of the tool with the options applied. The
+// parameters are copied too, so their schemas can be changed safely.
+func (t *mark3labsToolImpl) Customize(opts ...ToolOption) Tool {
+	tool := *t
+
+	tool.parameters = make([]ToolParameter, len(t.parameters))
+	for i, param := range t.parameters {
+		schema := make(map[string]interface{}, len(param.Schema))
+		for k, v := range param.Schema {
+			schema[k] = v
+		}
+		tool.parameters[i] = ToolParameter{Name: param.Name, Schema: schema}
+	}
+
+	for _, opt := range opts {
+		opt(&tool)
+	}
+	return &tool
+}
+
+// baseName returns the name the tool was created with
+func (t *mark3labsToolImpl) baseName() string {
+	return t.originalName
+}
+
 // GetHandler returns the handler for the tool
 func (t *mark3labsToolImpl) GetHandler() ToolHandler {
 	return t.handler
 }
 
+// GetAnnotations returns the behavior hints of the tool
+func (t *mark3labsToolImpl) GetAnnotations() ToolAnnotations {
+	return t.annotations
+}
+
+// SetDefaultAnnotations fills in the hints the tool did not set itself
+func (t *mark3labsToolImpl) SetDefaultAnnotations(defaults ToolAnnotations) {
+	t.annotations = t.annotations.withDefaults(defaults)
+}
+
+// GetTimeout returns the tool's own timeout, or 0 to use the default
+func (t *mark3labsToolImpl) GetTimeout() time.Duration {
+	return t.timeout
+}
+
+// GetConfirmation returns the confirmation describer of the tool
+func (t *mark3labsToolImpl) GetConfirmation() ConfirmationDescriber {
+	return t.confirmation
+}
+
+// getContextArguments returns the context arguments of the tool
+func (t *mark3labsToolImpl) getContextArguments() []contextArgument {
+	return t.contextArguments
+}
+
+// convertAnnotationsToToolOptions converts our annotations to mcp tool
+// options, leaving unset hints at the mcp defaults
+func convertAnnotationsToToolOptions(
+	annotations ToolAnnotations) []mcp.ToolOption {
+	var toolOpts []mcp.ToolOption
+
+	if annotations.Title != "" {
+		toolOpts = append(toolOpts, mcp.WithTitleAnnotation(annotations.Title))
+	}
+	if annotations.ReadOnlyHint != nil {
+		toolOpts = append(toolOpts,
+			mcp.WithReadOnlyHintAnnotation(*annotations.ReadOnlyHint))
+	}
+	if annotations.DestructiveHint != nil {
+		toolOpts = append(toolOpts,
+			mcp.WithDestructiveHintAnnotation(*annotations.DestructiveHint))
+	}
+	if annotations.IdempotentHint != nil {
+		toolOpts = append(toolOpts,
+			mcp.WithIdempotentHintAnnotation(*annotations.IdempotentHint))
+	}
+	if annotations.OpenWorldHint != nil {
+		toolOpts = append(toolOpts,
+			mcp.WithOpenWorldHintAnnotation(*annotations.OpenWorldHint))
+	}
+
+	return toolOpts
+}
+
 // toMCPServerTool converts our Tool to mcp's ServerTool
-func (t *mark3labsToolImpl) toMCPServerTool() server.ServerTool {
+func (t *mark3labsToolImpl) toMCPServerTool(
+	outer, inner toolMiddlewares) server.ServerTool {
 	// Create the mcp tool with appropriate options
 	var toolOpts []mcp.ToolOption
 
 	// Add description
 	toolOpts = append(toolOpts, mcp.WithDescription(t.description))
 
-	// Add parameters with their schemas
-	for _, param := range t.parameters {
+	// Add annotations
+	toolOpts = append(toolOpts, convertAnnotationsToToolOptions(t.annotations)...)
+
+	// Add parameters with their schemas, context arguments included
+	params := append([]ToolParameter{}, t.parameters...)
+	for _, contextArg := range t.contextArguments {
+		params = append(params, contextArg.param)
+	}
+	for _, param := range params {
 		// Get property options from schema
 		propOpts := convertSchemaToPropertyOptions(param.Schema)
 
@@ -413,12 +815,14 @@ func (t *mark3labsToolImpl) toMCPServerTool() server.ServerTool {
 		switch schemaType {
 		case "string":
 			toolOpts = append(toolOpts, mcp.WithString(param.Name, propOpts...))
-		case "number", "integer":
+		case "number":
 			toolOpts = append(toolOpts, mcp.WithNumber(param.Name, propOpts...))
+		case "integer":
+			toolOpts = append(toolOpts, withInteger(param.Name, propOpts...))
 		case "boolean":
 			toolOpts = append(toolOpts, mcp.WithBoolean(param.Name, propOpts...))
 		case "object":
-			toolOpts = append(toolOpts, mcp.WithObject(param.Name, propOpts...))
+			toolOpts = append(toolOpts, withObject(param.Name, param.Schema, propOpts...))
 		case "array":
 			toolOpts = append(toolOpts, mcp.WithArray(param.Name, propOpts...))
 		default:
@@ -431,6 +835,7 @@ func (t *mark3labsToolImpl) toMCPServerTool() server.ServerTool {
 	tool := mcp.NewTool(t.name, toolOpts...)
 
 	// Create the handler
+	handler := outer.chain(t, t.withArgumentValidation(inner.chain(t, t.handler)))
 	handlerFunc := func(
 		ctx context.Context,
 		req mcp.CallToolRequest,
@@ -439,10 +844,11 @@ func (t *mark3labsToolImpl) toMCPServerTool() server.ServerTool {
 		ourReq := CallToolRequest{
 			Name:      req.Params.Name,
 			Arguments: req.Params.Arguments,
+			Progress:  newProgressReporter(req),
 		}
 
 		// Call our handler
-		result, err := t.handler(ctx, ourReq)
+		result, err := handler(ctx, ourReq)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,57 @@
+package mcpgo
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNestedSchemasReachInputSchema(t *testing.T) {
+	tool := NewTool("nested", "Nested tool.", []ToolParameter{
+		WithArray("tags", Required(), Items(WithObject("", Properties(
+			WithInteger("id", Description("The tag ID.")),
+			WithString("name", Required()),
+		)))),
+		WithObject("collection", Properties(
+			WithString("name", Required()),
+			WithArray("aliases", Items(WithString(""))),
+		)),
+	}, func(ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+		return NewToolResultText("ok"), nil
+	})
+
+	data, err := json.Marshal(tool.toMCPServerTool(nil, nil).Tool.InputSchema)
+	require.NoError(t, err)
+
+	var schema map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &schema))
+
+	assert.Equal(t, []interface{}{"tags"}, schema["required"])
+
+	properties := schema["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"id":   map[string]interface{}{"type": "integer", "description": "The tag ID."},
+				"name": map[string]interface{}{"type": "string"},
+			},
+			"required": []interface{}{"name"},
+		},
+	}, properties["tags"])
+	assert.Equal(t, map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"name": map[string]interface{}{"type": "string"},
+			"aliases": map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"type": "string"},
+			},
+		},
+		"required": []interface{}{"name"},
+	}, properties["collection"])
+}
@@ -0,0 +1,297 @@
+package mcpgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// TypedToolHandler handles a tool call with its arguments decoded into Args.
+// A returned error is reported to the client as a tool error result.
+type TypedToolHandler[Args, Result any] func(
+	ctx context.Context,
+	request CallToolRequest,
+	args Args) (Result, error)
+
+// NewTypedTool creates a tool whose parameters are derived from the fields
+// of the Args struct. Each field is named by its json tag and described
+// by these optional tags:
+//
+//	description:"The ID of the link."
+//	required:"true"
+//	min:"1" max:"100"
+//	enum:"url,image,pdf"
+//	pattern:"^#[0-9a-f]{6}$"
+//
+// Embedded structs contribute their fields to the parent. A string Result
+// is returned as text, a *ToolResult as is, and anything else as JSON.
+// It panics if Args is not a struct or a tag is malformed, since that is
+// a programming error.
+func NewTypedTool[Args, Result any](
+	name,
+	description string,
+	handler TypedToolHandler[Args, Result],
+	opts ...ToolOption) *mark3labsToolImpl {
+	params, err := parametersFromStruct(reflect.TypeOf((*Args)(nil)).Elem())
+	if err != nil {
+		panic(fmt.Sprintf("tool %s: %v", name, err))
+	}
+
+	untyped := func(ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+		var args Args
+		if err := decodeArguments(req.Arguments, &args); err != nil {
+			return NewToolResultError(
+				"Validation errors:\n- " + err.Error()), nil
+		}
+
+		result, err := handler(ctx, req, args)
+		if err != nil {
+			return NewToolResultError(err.Error()), nil
+		}
+
+		switch r := any(result).(type) {
+		case *ToolResult:
+			return r, nil
+		case string:
+			return NewToolResultText(r), nil
+		default:
+			return NewToolResultJSON(r)
+		}
+	}
+
+	return NewTool(name, description, params, untyped, opts...)
+}
+
+// TypedConfirmationDescriber describes a tool call with its arguments
+// decoded into Args. A returned error aborts the call and is reported to
+// the client as a tool error result.
+type TypedConfirmationDescriber[Args any] func(
+	ctx context.Context,
+	request CallToolRequest,
+	args Args) (string, error)
+
+// NewTypedConfirmation creates a ConfirmationDescriber that decodes the
+// validated arguments into Args, like the handler of NewTypedTool
+func NewTypedConfirmation[Args any](describe TypedConfirmationDescriber[Args]) ConfirmationDescriber {
+	return func(ctx context.Context, req CallToolRequest) (string, *ToolResult, error) {
+		var args Args
+		if err := decodeArguments(req.Arguments, &args); err != nil {
+			return "", NewToolResultError(
+				"Validation errors:\n- " + err.Error()), nil
+		}
+
+		description, err := describe(ctx, req, args)
+		if err != nil {
+			return "", NewToolResultError(err.Error()), nil
+		}
+		return description, nil, nil
+	}
+}
+
+// decodeArguments decodes the validated arguments into the struct at target
+func decodeArguments(arguments any, target any) error {
+	if arguments == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(arguments)
+	if err != nil {
+		return errors.New("invalid arguments type")
+	}
+
+	if err := json.Unmarshal(data, target); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return fmt.Errorf("invalid parameter type: %s", typeErr.Field)
+		}
+		return errors.New("invalid arguments type")
+	}
+
+	return nil
+}
+
+// parametersFromStruct derives the tool parameters from the fields of t
+func parametersFromStruct(t reflect.Type) ([]ToolParameter, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("arguments must be a struct, got %s", t)
+	}
+
+	params := []ToolParameter{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name, ok := jsonFieldName(field)
+		if !ok {
+			continue
+		}
+
+		// Embedded structs without a name of their own are flattened
+		if field.Anonymous && name == "" {
+			embedded, err := parametersFromStruct(indirectType(field.Type))
+			if err != nil {
+				return nil, err
+			}
+			params = append(params, embedded...)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		param, err := parameterFromField(name, field)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, param)
+	}
+
+	return params, nil
+}
+
+// jsonFieldName returns the json name of the field, or false if the field
+// is not decoded from JSON
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() && !field.Anonymous {
+		return "", false
+	}
+
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+
+	name, _, _ := strings.Cut(tag, ",")
+	return name, true
+}
+
+// parameterFromField creates the parameter for a struct field from its type
+// and tags
+func parameterFromField(name string, field reflect.StructField) (ToolParameter, error) {
+	opts, err := propertyOptionsFromTags(name, field)
+	if err != nil {
+		return ToolParameter{}, err
+	}
+
+	return parameterFromType(name, field.Type, opts)
+}
+
+// parameterFromType creates a parameter of the JSON schema type matching t
+func parameterFromType(
+	name string, t reflect.Type, opts []PropertyOption) (ToolParameter, error) {
+	t = indirectType(t)
+
+	switch t.Kind() {
+	case reflect.String:
+		return WithString(name, opts...), nil
+	case reflect.Bool:
+		return WithBoolean(name, opts...), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return WithInteger(name, opts...), nil
+	case reflect.Float32, reflect.Float64:
+		return WithNumber(name, opts...), nil
+	case reflect.Slice, reflect.Array:
+		item, err := parameterFromType("", t.Elem(), nil)
+		if err != nil {
+			return ToolParameter{}, fmt.Errorf("%s: %w", name, err)
+		}
+		return WithArray(name, append(opts, Items(item))...), nil
+	case reflect.Map:
+		return WithObject(name, opts...), nil
+	case reflect.Struct:
+		props, err := parametersFromStruct(t)
+		if err != nil {
+			return ToolParameter{}, fmt.Errorf("%s: %w", name, err)
+		}
+		return WithObject(name, append(opts, Properties(props...))...), nil
+	default:
+		return ToolParameter{}, fmt.Errorf("%s: unsupported type %s", name, t)
+	}
+}
+
+// propertyOptionsFromTags converts the schema tags of a field into
+// property options
+func propertyOptionsFromTags(name string, field reflect.StructField) ([]PropertyOption, error) {
+	var opts []PropertyOption
+
+	if desc, ok := field.Tag.Lookup("description"); ok {
+		opts = append(opts, Description(desc))
+	}
+
+	if required, ok := field.Tag.Lookup("required"); ok {
+		isRequired, err := strconv.ParseBool(required)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid required tag %q", name, required)
+		}
+		if isRequired {
+			opts = append(opts, Required())
+		}
+	}
+
+	bounds := []struct {
+		tag    string
+		option func(float64) PropertyOption
+	}{{"min", Min}, {"max", Max}}
+	for _, bound := range bounds {
+		tag, option := bound.tag, bound.option
+		value, ok := field.Tag.Lookup(tag)
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid %s tag %q", name, tag, value)
+		}
+		opts = append(opts, option(n))
+	}
+
+	if pattern, ok := field.Tag.Lookup("pattern"); ok {
+		opts = append(opts, Pattern(pattern))
+	}
+
+	if enum, ok := field.Tag.Lookup("enum"); ok {
+		values, err := enumValues(indirectType(field.Type), strings.Split(enum, ","))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		opts = append(opts, Enum(values...))
+	}
+
+	return opts, nil
+}
+
+// enumValues converts the enum tag values to the field's type
+func enumValues(t reflect.Type, raw []string) ([]interface{}, error) {
+	values := make([]interface{}, 0, len(raw))
+	for _, value := range raw {
+		value = strings.TrimSpace(value)
+
+		switch t.Kind() {
+		case reflect.String:
+			values = append(values, value)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid enum value %q", value)
+			}
+			values = append(values, n)
+		default:
+			return nil, fmt.Errorf("enum is not supported for %s", t)
+		}
+	}
+	return values, nil
+}
+
+// indirectType returns the type pointed to by pointer types
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
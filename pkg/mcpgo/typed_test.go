@@ -0,0 +1,84 @@
+package mcpgo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type typedPaging struct {
+	Cursor *int `json:"cursor,omitempty" description:"Pagination cursor." min:"0"`
+}
+
+type typedArgs struct {
+	Id   int      `json:"id" description:"The ID." required:"true" min:"1"`
+	Type *string  `json:"type,omitempty" enum:"url,image"`
+	Tags []string `json:"tags,omitempty"`
+	typedPaging
+}
+
+func TestNewTypedToolDerivesSchema(t *testing.T) {
+	tool := NewTypedTool("typed", "Typed tool.",
+		func(ctx context.Context, req CallToolRequest, args typedArgs) (string, error) {
+			return "", nil
+		})
+
+	schema := tool.toMCPServerTool(nil, nil).Tool.InputSchema
+	assert.Equal(t, []string{"id"}, schema.Required)
+	assert.Equal(t, map[string]interface{}{
+		"type":        "integer",
+		"description": "The ID.",
+		"minimum":     float64(1),
+	}, schema.Properties["id"])
+	assert.Equal(t, map[string]interface{}{
+		"type": "string",
+		"enum": []string{"url", "image"},
+	}, schema.Properties["type"])
+	assert.Equal(t, map[string]interface{}{
+		"type":  "array",
+		"items": map[string]interface{}{"type": "string"},
+	}, schema.Properties["tags"])
+	assert.Contains(t, schema.Properties, "cursor")
+}
+
+func TestNewTypedToolDecodesArguments(t *testing.T) {
+	var got typedArgs
+	tool := NewTypedTool("typed", "Typed tool.",
+		func(ctx context.Context, req CallToolRequest, args typedArgs) (map[string]int, error) {
+			got = args
+			return map[string]int{"id": args.Id}, nil
+		})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "typed"
+	req.Params.Arguments = map[string]interface{}{
+		"id":     "7",
+		"tags":   []interface{}{"go"},
+		"cursor": 3.0,
+	}
+
+	result, err := tool.toMCPServerTool(nil, nil).Handler(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Equal(t, 7, got.Id)
+	assert.Equal(t, []string{"go"}, got.Tags)
+	require.NotNil(t, got.Cursor)
+	assert.Equal(t, 3, *got.Cursor)
+}
+
+func TestNewTypedToolReportsHandlerErrors(t *testing.T) {
+	tool := NewTypedTool("typed", "Typed tool.",
+		func(ctx context.Context, req CallToolRequest, args struct{}) (string, error) {
+			return "", assert.AnError
+		})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "typed"
+
+	result, err := tool.toMCPServerTool(nil, nil).Handler(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
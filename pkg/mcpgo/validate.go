@@ -0,0 +1,301 @@
+package mcpgo
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// withArgumentValidation wraps the tool handler so it only runs with
+// arguments that match the declared parameters. Numeric strings are coerced
+// to numbers before the handler sees them.
+func (t *mark3labsToolImpl) withArgumentValidation(next ToolHandler) ToolHandler {
+	return func(ctx context.Context, req CallToolRequest) (*ToolResult, error) {
+		args, errs := validateArguments(t.parameters, req.Arguments)
+		if len(errs) > 0 {
+			return NewToolResultError(
+				"Validation errors:\n- " + strings.Join(errs, "\n- ")), nil
+		}
+
+		req.Arguments = args
+		return next(ctx, req)
+	}
+}
+
+// validateArguments checks the arguments against the parameters and
+// returns the coerced arguments along with every violation found
+func validateArguments(
+	params []ToolParameter, arguments any) (map[string]interface{}, []string) {
+	args := map[string]interface{}{}
+	if arguments != nil {
+		var ok bool
+		if args, ok = arguments.(map[string]interface{}); !ok {
+			return nil, []string{"invalid arguments type"}
+		}
+	}
+
+	coerced := make(map[string]interface{}, len(args))
+	var errs []string
+
+	declared := make(map[string]bool, len(params))
+	for _, param := range params {
+		declared[param.Name] = true
+
+		value, ok := args[param.Name]
+		if !ok || value == nil {
+			if required, _ := param.Schema["required"].(bool); required {
+				errs = append(errs, "missing required parameter: "+param.Name)
+			}
+			continue
+		}
+
+		value, paramErrs := validateValue(param.Name, param.jsonSchema(), value)
+		errs = append(errs, paramErrs...)
+		coerced[param.Name] = value
+	}
+
+	errs = append(errs, unknownParameters(args, declared, "")...)
+
+	return coerced, errs
+}
+
+// unknownParameters reports the arguments that are not declared
+func unknownParameters(
+	args map[string]interface{}, declared map[string]bool, prefix string) []string {
+	var unknown []string
+	for name := range args {
+		if !declared[name] {
+			unknown = append(unknown, "unknown parameter: "+prefix+name)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// validateValue checks a value against a JSON schema, returning the value
+// with numeric strings coerced and the violations found at path
+func validateValue(
+	path string, schema map[string]interface{}, value any) (any, []string) {
+	var errs []string
+
+	switch schema["type"] {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return value, []string{"invalid parameter type: " + path}
+		}
+		errs = append(errs, validateString(path, schema, s)...)
+	case "number", "integer":
+		n, ok := toNumber(value)
+		if !ok {
+			return value, []string{"invalid parameter type: " + path}
+		}
+		value = n
+		if schema["type"] == "integer" && n != math.Trunc(n) {
+			return value, []string{"parameter " + path + " must be an integer"}
+		}
+		errs = append(errs, validateNumber(path, schema, n)...)
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return value, []string{"invalid parameter type: " + path}
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return value, []string{"invalid parameter type: " + path}
+		}
+		var itemErrs []string
+		value, itemErrs = validateArray(path, schema, items)
+		errs = append(errs, itemErrs...)
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return value, []string{"invalid parameter type: " + path}
+		}
+		var propErrs []string
+		value, propErrs = validateObject(path, schema, obj)
+		errs = append(errs, propErrs...)
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && !inEnum(enum, value) {
+		values := make([]string, 0, len(enum))
+		for _, v := range enum {
+			values = append(values, fmt.Sprint(v))
+		}
+		errs = append(errs, fmt.Sprintf("parameter %s must be one of: %s",
+			path, strings.Join(values, ", ")))
+	}
+
+	return value, errs
+}
+
+// validateString checks the length and pattern constraints of a string
+func validateString(path string, schema map[string]interface{}, s string) []string {
+	var errs []string
+	length := len([]rune(s))
+
+	if minLength, ok := schema["minLength"].(int); ok && length < minLength {
+		errs = append(errs, fmt.Sprintf(
+			"parameter %s must be at least %d characters", path, minLength))
+	}
+	if maxLength, ok := schema["maxLength"].(int); ok && length > maxLength {
+		errs = append(errs, fmt.Sprintf(
+			"parameter %s must be at most %d characters", path, maxLength))
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		re, err := regexp.Compile(pattern)
+		if err == nil && !re.MatchString(s) {
+			errs = append(errs, fmt.Sprintf(
+				"parameter %s must match pattern %s", path, pattern))
+		}
+	}
+
+	return errs
+}
+
+// validateNumber checks the range constraints of a number
+func validateNumber(path string, schema map[string]interface{}, n float64) []string {
+	var errs []string
+
+	if minimum, ok := schema["minimum"].(float64); ok && n < minimum {
+		errs = append(errs, fmt.Sprintf("parameter %s must be at least %g", path, minimum))
+	}
+	if maximum, ok := schema["maximum"].(float64); ok && n > maximum {
+		errs = append(errs, fmt.Sprintf("parameter %s must be at most %g", path, maximum))
+	}
+
+	return errs
+}
+
+// validateArray checks the size of an array and each of its items
+func validateArray(
+	path string, schema map[string]interface{}, items []interface{}) ([]interface{}, []string) {
+	var errs []string
+
+	if minItems, ok := schema["minItems"].(int); ok && len(items) < minItems {
+		errs = append(errs, fmt.Sprintf(
+			"parameter %s must have at least %d items", path, minItems))
+	}
+	if maxItems, ok := schema["maxItems"].(int); ok && len(items) > maxItems {
+		errs = append(errs, fmt.Sprintf(
+			"parameter %s must have at most %d items", path, maxItems))
+	}
+
+	itemSchema, ok := schema["items"].(map[string]interface{})
+	if !ok {
+		return items, errs
+	}
+
+	coerced := make([]interface{}, len(items))
+	for i, item := range items {
+		value, itemErrs := validateValue(fmt.Sprintf("%s[%d]", path, i), itemSchema, item)
+		coerced[i] = value
+		errs = append(errs, itemErrs...)
+	}
+
+	return coerced, errs
+}
+
+// validateObject checks the declared properties of an object. Objects
+// without declared properties accept anything.
+func validateObject(
+	path string, schema map[string]interface{}, obj map[string]interface{},
+) (map[string]interface{}, []string) {
+	var errs []string
+
+	if minProps, ok := schema["minProperties"].(int); ok && len(obj) < minProps {
+		errs = append(errs, fmt.Sprintf(
+			"parameter %s must have at least %d properties", path, minProps))
+	}
+	if maxProps, ok := schema["maxProperties"].(int); ok && len(obj) > maxProps {
+		errs = append(errs, fmt.Sprintf(
+			"parameter %s must have at most %d properties", path, maxProps))
+	}
+
+	properties, ok := schema["properties"].(map[string]interface{})
+	if !ok {
+		return obj, errs
+	}
+
+	required := map[string]bool{}
+	if names, ok := schema["required"].([]string); ok {
+		for _, name := range names {
+			required[name] = true
+		}
+	}
+
+	// Check properties in a stable order so errors are reported consistently
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	coerced := make(map[string]interface{}, len(obj))
+	declared := make(map[string]bool, len(properties))
+	for _, name := range names {
+		declared[name] = true
+
+		value, ok := obj[name]
+		if !ok || value == nil {
+			if required[name] {
+				errs = append(errs, "missing required parameter: "+path+"."+name)
+			}
+			continue
+		}
+
+		propSchema, _ := properties[name].(map[string]interface{})
+		value, propErrs := validateValue(path+"."+name, propSchema, value)
+		coerced[name] = value
+		errs = append(errs, propErrs...)
+	}
+
+	errs = append(errs, unknownParameters(obj, declared, path+".")...)
+
+	return coerced, errs
+}
+
+// toNumber converts JSON numbers and numeric strings to float64
+func toNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+			return 0, false
+		}
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+// inEnum reports whether value is one of the allowed values, comparing
+// numbers by value regardless of their Go type
+func inEnum(enum []interface{}, value any) bool {
+	for _, allowed := range enum {
+		if a, ok := toNumber(allowed); ok {
+			if _, isString := allowed.(string); !isString {
+				if v, ok := toNumber(value); ok && a == v {
+					return true
+				}
+				continue
+			}
+		}
+		if allowed == value {
+			return true
+		}
+	}
+	return false
+}
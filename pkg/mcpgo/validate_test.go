@@ -0,0 +1,79 @@
+package mcpgo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateArguments(t *testing.T) {
+	params := []ToolParameter{
+		WithInteger("id", Required(), Min(1)),
+		WithString("sort", Enum("asc", "desc")),
+		WithString("color", Pattern("^#[0-9a-f]{6}$")),
+		WithArray("tags", Items(WithObject("", Properties(
+			WithInteger("id"),
+			WithString("name", Required()),
+		)))),
+	}
+
+	tests := []struct {
+		name     string
+		args     map[string]interface{}
+		expected map[string]interface{}
+		errs     []string
+	}{
+		{
+			name:     "coerces numeric strings",
+			args:     map[string]interface{}{"id": "42"},
+			expected: map[string]interface{}{"id": float64(42)},
+		},
+		{
+			name: "reports missing and unknown parameters",
+			args: map[string]interface{}{"ID": 1.0},
+			errs: []string{
+				"missing required parameter: id",
+				"unknown parameter: ID",
+			},
+		},
+		{
+			name: "reports every constraint violation",
+			args: map[string]interface{}{
+				"id":    "1.5",
+				"sort":  "newest",
+				"color": "red",
+			},
+			errs: []string{
+				"parameter id must be an integer",
+				"parameter sort must be one of: asc, desc",
+				"parameter color must match pattern ^#[0-9a-f]{6}$",
+			},
+		},
+		{
+			name: "validates nested items",
+			args: map[string]interface{}{
+				"id": 0.0,
+				"tags": []interface{}{
+					map[string]interface{}{"id": "x", "label": "go"},
+				},
+			},
+			errs: []string{
+				"parameter id must be at least 1",
+				"invalid parameter type: tags[0].id",
+				"missing required parameter: tags[0].name",
+				"unknown parameter: tags[0].label",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, errs := validateArguments(params, tt.args)
+
+			assert.Equal(t, tt.errs, errs)
+			if tt.expected != nil {
+				assert.Equal(t, tt.expected, args)
+			}
+		})
+	}
+}
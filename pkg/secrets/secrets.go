@@ -0,0 +1,191 @@
+// Package secrets loads secrets such as the Linkwarden token from the
+// config, a file or a command, so they stay out of shell history and
+// process listings.
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Provider returns the current value of a secret. Providers are asked
+// again whenever the secret may have changed, e.g. after a rotation.
+type Provider interface {
+	Secret(ctx context.Context) (string, error)
+}
+
+// ProviderFunc adapts a function to the Provider interface
+type ProviderFunc func(ctx context.Context) (string, error)
+
+// Secret calls f
+func (f ProviderFunc) Secret(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// Static returns a provider for a secret given in the config
+func Static(secret string) Provider {
+	return ProviderFunc(func(ctx context.Context) (string, error) {
+		return secret, nil
+	})
+}
+
+// File returns a provider reading the secret from the file at path, such
+// as a Docker or Kubernetes secret mount. Surrounding whitespace is
+// trimmed.
+func File(path string) Provider {
+	return ProviderFunc(func(ctx context.Context) (string, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret file: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	})
+}
+
+// Command returns a provider running command in the shell and using its
+// output as the secret, e.g. to ask a password manager or the OS keyring.
+// Surrounding whitespace is trimmed.
+func Command(command string) Provider {
+	return ProviderFunc(func(ctx context.Context) (string, error) {
+		var cmd *exec.Cmd
+		if runtime.GOOS == "windows" {
+			cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+		} else {
+			cmd = exec.CommandContext(ctx, "sh", "-c", command)
+		}
+
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+
+		out, err := cmd.Output()
+		if err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return "", fmt.Errorf("secret command failed: %w: %s", err, msg)
+			}
+			return "", fmt.Errorf("secret command failed: %w", err)
+		}
+		return strings.TrimSpace(string(out)), nil
+	})
+}
+
+// FromConfig returns the provider for a secret set in one of three ways:
+// directly, as a file or as a command. Setting more than one is an error.
+func FromConfig(secret, file, command string) (Provider, error) {
+	set := 0
+	for _, value := range []string{secret, file, command} {
+		if value != "" {
+			set++
+		}
+	}
+	if set > 1 {
+		return nil, errors.New("set only one of token, token_file and token_command")
+	}
+
+	switch {
+	case file != "":
+		return File(file), nil
+	case command != "":
+		return Command(command), nil
+	default:
+		return Static(secret), nil
+	}
+}
+
+// Cached remembers the secret of a provider until it is refreshed, so the
+// provider is not asked on every request. Concurrent callers share a
+// single provider call, which runs without holding the lock.
+type Cached struct {
+	provider Provider
+
+	mu     sync.Mutex
+	secret string
+	loaded bool
+	fetch  *fetch
+}
+
+// fetch is a provider call shared by the callers waiting for it
+type fetch struct {
+	done   chan struct{}
+	secret string
+	err    error
+}
+
+// NewCached returns a cache for the secret of provider
+func NewCached(provider Provider) *Cached {
+	return &Cached{provider: provider}
+}
+
+// Secret returns the cached secret, asking the provider the first time
+func (c *Cached) Secret(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	if c.loaded {
+		defer c.mu.Unlock()
+		return c.secret, nil
+	}
+	f := c.startFetch(ctx)
+	c.mu.Unlock()
+
+	return f.wait(ctx)
+}
+
+// Refresh asks the provider again and reports whether the secret changed.
+// Refreshes during a provider call share its result.
+func (c *Cached) Refresh(ctx context.Context) (bool, error) {
+	c.mu.Lock()
+	previous, loaded := c.secret, c.loaded
+	f := c.startFetch(ctx)
+	c.mu.Unlock()
+
+	secret, err := f.wait(ctx)
+	if err != nil {
+		return false, err
+	}
+	return !loaded || secret != previous, nil
+}
+
+// startFetch returns the running provider call, starting one if there is
+// none. c.mu must be held.
+func (c *Cached) startFetch(ctx context.Context) *fetch {
+	if c.fetch != nil {
+		return c.fetch
+	}
+
+	f := &fetch{done: make(chan struct{})}
+	c.fetch = f
+
+	// The call is shared, so it must not stop when the first caller
+	// gives up waiting
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		secret, err := c.provider.Secret(ctx)
+
+		c.mu.Lock()
+		if err == nil {
+			c.secret, c.loaded = secret, true
+		}
+		c.fetch = nil
+		c.mu.Unlock()
+
+		f.secret, f.err = secret, err
+		close(f.done)
+	}()
+	return f
+}
+
+// wait returns the result of the provider call, or ctx's error if ctx is
+// done first
+func (f *fetch) wait(ctx context.Context) (string, error) {
+	select {
+	case <-f.done:
+		return f.secret, f.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
@@ -0,0 +1,107 @@
+package secrets
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileAndCommandTrimTheSecret(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte("from-file\n"), 0o600))
+
+	secret, err := File(path).Secret(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "from-file", secret)
+
+	if runtime.GOOS == "windows" {
+		t.Skip("command test needs a POSIX shell")
+	}
+
+	secret, err = Command("echo from-command").Secret(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "from-command", secret)
+
+	_, err = Command("echo locked >&2; exit 1").Secret(context.Background())
+	assert.ErrorContains(t, err, "locked")
+}
+
+func TestFromConfigAllowsOneSource(t *testing.T) {
+	_, err := FromConfig("token", "", "pass show linkwarden")
+	assert.EqualError(t, err, "set only one of token, token_file and token_command")
+
+	provider, err := FromConfig("token", "", "")
+	require.NoError(t, err)
+	secret, err := provider.Secret(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "token", secret)
+}
+
+func TestCachedRefreshReportsChanges(t *testing.T) {
+	current := "old"
+	cached := NewCached(ProviderFunc(func(ctx context.Context) (string, error) {
+		return current, nil
+	}))
+	ctx := context.Background()
+
+	secret, err := cached.Secret(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "old", secret)
+
+	changed, err := cached.Refresh(ctx)
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	current = "new"
+	secret, _ = cached.Secret(ctx)
+	assert.Equal(t, "old", secret, "the secret is cached until refreshed")
+
+	changed, err = cached.Refresh(ctx)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	secret, _ = cached.Secret(ctx)
+	assert.Equal(t, "new", secret)
+}
+
+func TestCachedSharesASlowProviderCall(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var calls atomic.Int32
+	cached := NewCached(ProviderFunc(func(ctx context.Context) (string, error) {
+		if calls.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		return "token", nil
+	}))
+
+	secrets := make(chan string, 1)
+	go func() {
+		secret, _ := cached.Secret(context.Background())
+		secrets <- secret
+	}()
+	<-started
+
+	// Callers joining the running call are not blocked on it
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := cached.Refresh(cancelled)
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = cached.Secret(cancelled)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	close(release)
+	assert.Equal(t, "token", <-secrets)
+	assert.Equal(t, int32(1), calls.Load())
+
+	secret, err := cached.Secret(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "token", secret)
+	assert.Equal(t, int32(1), calls.Load())
+}
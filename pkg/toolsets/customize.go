@@ -0,0 +1,132 @@
+package toolsets
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/mcpgo"
+)
+
+// ToolCustomization changes how tools are presented to clients without
+// changing what they do. Prefix is prepended to every tool name, and
+// Overrides replace descriptions keyed by the unprefixed tool name.
+type ToolCustomization struct {
+	Prefix    string
+	Overrides map[string]ToolOverride
+}
+
+// ToolOverride replaces the description of a tool and of its parameters.
+// Empty descriptions keep the built-in ones.
+type ToolOverride struct {
+	Description string            `mapstructure:"description"`
+	Parameters  map[string]string `mapstructure:"parameters"`
+}
+
+// options returns the tool options applying the customization to tool
+func (c ToolCustomization) options(tool mcpgo.Tool) []mcpgo.ToolOption {
+	var opts []mcpgo.ToolOption
+	if c.Prefix != "" {
+		opts = append(opts, mcpgo.WithNamePrefix(c.Prefix))
+	}
+
+	override, ok := c.Overrides[tool.GetName()]
+	if !ok {
+		return opts
+	}
+	if override.Description != "" {
+		opts = append(opts, mcpgo.WithDescription(override.Description))
+	}
+	for param, description := range override.Parameters {
+		if description != "" {
+			opts = append(opts, mcpgo.WithParameterDescription(param, description))
+		}
+	}
+	return opts
+}
+
+// apply returns a customized copy of tool, or tool itself when nothing
+// needs to change
+func (c ToolCustomization) apply(tool mcpgo.Tool) mcpgo.Tool {
+	opts := c.options(tool)
+	if len(opts) == 0 {
+		return tool
+	}
+	return tool.Customize(opts...)
+}
+
+// normalize checks that every override names an existing tool and
+// parameter, and returns the overrides keyed by their canonical names.
+// Names are matched case-insensitively because configuration keys are.
+func (c ToolCustomization) normalize(tools []mcpgo.Tool) (map[string]ToolOverride, error) {
+	byName := make(map[string]mcpgo.Tool, len(tools))
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		byName[strings.ToLower(tool.GetName())] = tool
+		names = append(names, tool.GetName())
+	}
+	sort.Strings(names)
+
+	overrides := make(map[string]ToolOverride, len(c.Overrides))
+	for name, override := range c.Overrides {
+		tool, exists := byName[strings.ToLower(name)]
+		if !exists {
+			return nil, fmt.Errorf(
+				"override for unknown tool %q, available tools: %s",
+				name, strings.Join(names, ", "))
+		}
+
+		params := make(map[string]string, len(override.Parameters))
+		for param, description := range override.Parameters {
+			canonical, err := parameterName(tool, param)
+			if err != nil {
+				return nil, err
+			}
+			params[canonical] = description
+		}
+
+		overrides[tool.GetName()] = ToolOverride{
+			Description: override.Description,
+			Parameters:  params,
+		}
+	}
+
+	return overrides, nil
+}
+
+// parameterName returns the name of the tool parameter matching param
+func parameterName(tool mcpgo.Tool, param string) (string, error) {
+	var names []string
+	for _, p := range tool.GetParameters() {
+		if strings.EqualFold(p.Name, param) {
+			return p.Name, nil
+		}
+		names = append(names, p.Name)
+	}
+	return "", fmt.Errorf(
+		"override for unknown parameter %q of tool %s, available parameters: %s",
+		param, tool.GetName(), strings.Join(names, ", "))
+}
+
+// SetToolCustomization applies the customization to every toolset in the
+// group and to the tools of dynamic mode. It must be called after the
+// toolsets are added and fails when an override names an unknown tool
+// or parameter.
+func (tg *ToolsetGroup) SetToolCustomization(c ToolCustomization) error {
+	var tools []mcpgo.Tool
+	for _, toolset := range tg.Toolsets {
+		tools = append(tools, toolset.allTools()...)
+	}
+
+	overrides, err := c.normalize(tools)
+	if err != nil {
+		return err
+	}
+	c.Overrides = overrides
+
+	tg.customization = c
+	for _, toolset := range tg.Toolsets {
+		toolset.customization = c
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package toolsets
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/mcpgo"
+)
+
+func newCustomizeTestGroup() *ToolsetGroup {
+	handler := func(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.ToolResult, error) {
+		return mcpgo.NewToolResultText("ok"), nil
+	}
+
+	group := NewToolsetGroup(false)
+	group.AddToolset(NewToolset("link", "links").
+		AddReadTools(mcpgo.NewTool("get_link_by_id", "Gets a link.",
+			[]mcpgo.ToolParameter{
+				mcpgo.WithNumber("id", mcpgo.Description("The link ID.")),
+				mcpgo.WithNumber("collectionId", mcpgo.Description("The collection ID.")),
+			}, handler)))
+	return group
+}
+
+func TestSetToolCustomization(t *testing.T) {
+	group := newCustomizeTestGroup()
+	original := group.Toolsets["link"].allTools()[0]
+
+	// Keys arrive lowercased from the config file
+	err := group.SetToolCustomization(ToolCustomization{
+		Prefix: "lw_",
+		Overrides: map[string]ToolOverride{
+			"get_link_by_id": {
+				Description: "Looks up a bookmark.",
+				Parameters:  map[string]string{"collectionid": "The folder ID."},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	tools := group.Toolsets["link"].GetActiveTools()
+	require.Len(t, tools, 1)
+	assert.Equal(t, "lw_get_link_by_id", tools[0].GetName())
+	assert.Equal(t, "Looks up a bookmark.", tools[0].GetDescription())
+
+	params := tools[0].GetParameters()
+	assert.Equal(t, "The link ID.", params[0].Schema["description"])
+	assert.Equal(t, "The folder ID.", params[1].Schema["description"])
+
+	// The registered copy leaves the original tool untouched
+	assert.Equal(t, "get_link_by_id", original.GetName())
+	assert.Equal(t, "The collection ID.", original.GetParameters()[1].Schema["description"])
+}
+
+func TestSetToolCustomizationRejectsUnknownNames(t *testing.T) {
+	group := newCustomizeTestGroup()
+
+	err := group.SetToolCustomization(ToolCustomization{
+		Overrides: map[string]ToolOverride{"get_link": {Description: "x"}},
+	})
+	assert.EqualError(t, err, `override for unknown tool "get_link", available tools: get_link_by_id`)
+
+	err = group.SetToolCustomization(ToolCustomization{
+		Overrides: map[string]ToolOverride{
+			"get_link_by_id": {Parameters: map[string]string{"url": "x"}},
+		},
+	})
+	assert.EqualError(t, err, `override for unknown parameter "url" of tool get_link_by_id, `+
+		"available parameters: id, collectionId")
+}
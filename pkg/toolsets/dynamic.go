@@ -0,0 +1,179 @@
+package toolsets
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/mcpgo"
+)
+
+// toolsetSummary describes a toolset in list_available_toolsets
+type toolsetSummary struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+	ToolCount   int    `json:"toolCount"`
+}
+
+// toolSummary describes a tool in get_toolset_tools
+type toolSummary struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	ReadOnly    bool   `json:"readOnly"`
+}
+
+// dynamicTools returns the tools to discover and enable toolsets
+func (tg *ToolsetGroup) dynamicTools() []mcpgo.Tool {
+	tools := []mcpgo.Tool{
+		tg.listAvailableToolsets(),
+		tg.getToolsetTools(),
+		tg.enableToolset(),
+	}
+	// None of them change Linkwarden data, so they stay available in
+	// read-only mode
+	applyDefaultAnnotations(tools, readToolAnnotations)
+
+	for i, tool := range tools {
+		tools[i] = tg.customization.apply(tool)
+	}
+	return tools
+}
+
+// listAvailableToolsets returns a tool listing every toolset
+func (tg *ToolsetGroup) listAvailableToolsets() mcpgo.Tool {
+	handler := func(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.ToolResult, error) {
+		tg.mu.Lock()
+		defer tg.mu.Unlock()
+
+		summaries := make([]toolsetSummary, 0, len(tg.Toolsets))
+		for _, toolset := range tg.Toolsets {
+			summaries = append(summaries, toolsetSummary{
+				Name:        toolset.Name,
+				Description: toolset.Description,
+				Enabled:     toolset.Enabled,
+				ToolCount:   len(toolset.GetActiveTools()),
+			})
+		}
+		sort.Slice(summaries, func(i, j int) bool {
+			return summaries[i].Name < summaries[j].Name
+		})
+
+		return mcpgo.NewToolResultJSON(summaries)
+	}
+
+	return mcpgo.NewTool(
+		"list_available_toolsets",
+		"Lists the toolsets that can be enabled, and whether they already are. "+
+			"Use this to find tools for a task before calling "+
+			tg.customization.Prefix+"enable_toolset.",
+		[]mcpgo.ToolParameter{},
+		handler,
+	)
+}
+
+// getToolsetTools returns a tool listing the tools of a toolset
+func (tg *ToolsetGroup) getToolsetTools() mcpgo.Tool {
+	params := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"toolset",
+			mcpgo.Description("The name of the toolset."),
+			mcpgo.Required(),
+		),
+	}
+
+	handler := func(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.ToolResult, error) {
+		name, result := toolsetNameFromRequest(req)
+		if result != nil {
+			return result, nil
+		}
+
+		tg.mu.Lock()
+		defer tg.mu.Unlock()
+
+		toolset, exists := tg.Toolsets[name]
+		if !exists {
+			return mcpgo.NewToolResultError(fmt.Sprintf("toolset %s does not exist", name)), nil
+		}
+
+		tools := toolset.GetActiveTools()
+		summaries := make([]toolSummary, 0, len(tools))
+		for _, tool := range tools {
+			readOnly := tool.GetAnnotations().ReadOnlyHint
+			summaries = append(summaries, toolSummary{
+				Name:        tool.GetName(),
+				Description: tool.GetDescription(),
+				ReadOnly:    readOnly != nil && *readOnly,
+			})
+		}
+
+		return mcpgo.NewToolResultJSON(summaries)
+	}
+
+	return mcpgo.NewTool(
+		"get_toolset_tools",
+		"Lists the tools a toolset provides.",
+		params,
+		handler,
+	)
+}
+
+// enableToolset returns a tool registering the tools of a toolset
+func (tg *ToolsetGroup) enableToolset() mcpgo.Tool {
+	params := []mcpgo.ToolParameter{
+		mcpgo.WithString(
+			"toolset",
+			mcpgo.Description("The name of the toolset to enable."),
+			mcpgo.Required(),
+		),
+	}
+
+	handler := func(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.ToolResult, error) {
+		name, result := toolsetNameFromRequest(req)
+		if result != nil {
+			return result, nil
+		}
+
+		tg.mu.Lock()
+		defer tg.mu.Unlock()
+
+		toolset, exists := tg.Toolsets[name]
+		if !exists {
+			return mcpgo.NewToolResultError(fmt.Sprintf("toolset %s does not exist", name)), nil
+		}
+
+		if toolset.Enabled {
+			return mcpgo.NewToolResultText(fmt.Sprintf("Toolset %s is already enabled", name)), nil
+		}
+
+		toolset.Enabled = true
+		toolset.RegisterTools(tg.server)
+
+		names := make([]string, 0, len(toolset.GetActiveTools()))
+		for _, tool := range toolset.GetActiveTools() {
+			names = append(names, tool.GetName())
+		}
+
+		return mcpgo.NewToolResultText(fmt.Sprintf("Enabled toolset %s with tools: %s",
+			name, strings.Join(names, ", "))), nil
+	}
+
+	return mcpgo.NewTool(
+		"enable_toolset",
+		"Enables a toolset, making its tools available.",
+		params,
+		handler,
+	)
+}
+
+// toolsetNameFromRequest returns the toolset argument of the request, or an
+// error result if it is missing
+func toolsetNameFromRequest(req mcpgo.CallToolRequest) (string, *mcpgo.ToolResult) {
+	args, _ := req.Arguments.(map[string]interface{})
+	name, _ := args["toolset"].(string)
+	if name == "" {
+		return "", mcpgo.NewToolResultError("missing required parameter: toolset")
+	}
+	return name, nil
+}
@@ -0,0 +1,95 @@
+package toolsets
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/mcpgo"
+)
+
+// newDynamicTestGroup returns a dynamic group registered with server, and
+// a function calling its dynamic tools by name
+func newDynamicTestGroup(t *testing.T, server *recordingServer) func(
+	name string, args map[string]interface{}) *mcpgo.ToolResult {
+	group := newFilterTestGroup()
+	group.EnableDynamicMode()
+	require.NoError(t, group.EnableToolsets(nil))
+	group.RegisterTools(server)
+
+	tools := make(map[string]mcpgo.Tool)
+	for _, tool := range group.dynamicTools() {
+		tools[tool.GetName()] = tool
+	}
+
+	return func(name string, args map[string]interface{}) *mcpgo.ToolResult {
+		result, err := tools[name].GetHandler()(context.Background(),
+			mcpgo.CallToolRequest{Name: name, Arguments: args})
+		require.NoError(t, err)
+		return result
+	}
+}
+
+func TestEnableToolsetRegistersToolsOnce(t *testing.T) {
+	server := &recordingServer{}
+	call := newDynamicTestGroup(t, server)
+	assert.Equal(t, []string{"enable_toolset", "get_toolset_tools", "list_available_toolsets"}, server.tools)
+
+	result := call("enable_toolset", map[string]interface{}{"toolset": "link"})
+	assert.False(t, result.IsError)
+	assert.Equal(t, "Enabled toolset link with tools: get_all_links, get_link_by_id, "+
+		"create_link, delete_link_by_id, delete_links", result.Text)
+
+	// Enabling it again is a no-op
+	result = call("enable_toolset", map[string]interface{}{"toolset": "link"})
+	assert.False(t, result.IsError)
+	assert.Equal(t, "Toolset link is already enabled", result.Text)
+
+	assert.Equal(t, []string{
+		"enable_toolset", "get_toolset_tools", "list_available_toolsets",
+		"create_link", "delete_link_by_id", "delete_links", "get_all_links", "get_link_by_id",
+	}, server.tools)
+}
+
+func TestDynamicToolsRejectUnknownToolsets(t *testing.T) {
+	server := &recordingServer{}
+	call := newDynamicTestGroup(t, server)
+
+	for _, name := range []string{"enable_toolset", "get_toolset_tools"} {
+		result := call(name, map[string]interface{}{"toolset": "bookmarks"})
+		assert.True(t, result.IsError)
+		assert.Equal(t, "toolset bookmarks does not exist", result.Text)
+
+		result = call(name, nil)
+		assert.True(t, result.IsError)
+		assert.Equal(t, "missing required parameter: toolset", result.Text)
+	}
+	assert.Len(t, server.tools, 3)
+}
+
+func TestListAvailableToolsetsReportsEnabledState(t *testing.T) {
+	server := &recordingServer{}
+	call := newDynamicTestGroup(t, server)
+
+	list := func() []toolsetSummary {
+		var summaries []toolsetSummary
+		require.NoError(t, json.Unmarshal([]byte(call("list_available_toolsets", nil).Text), &summaries))
+		return summaries
+	}
+
+	assert.Equal(t, []toolsetSummary{
+		{Name: "link", Description: "links", Enabled: false, ToolCount: 5},
+	}, list())
+
+	call("enable_toolset", map[string]interface{}{"toolset": "link"})
+	assert.True(t, list()[0].Enabled)
+
+	var tools []toolSummary
+	require.NoError(t, json.Unmarshal(
+		[]byte(call("get_toolset_tools", map[string]interface{}{"toolset": "link"}).Text), &tools))
+	require.Len(t, tools, 5)
+	assert.Equal(t, "get_all_links", tools[0].Name)
+}
@@ -0,0 +1,106 @@
+package toolsets
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/mcpgo"
+)
+
+// ToolFilter selects individual tools within the enabled toolsets using
+// glob patterns such as delete_*. Without Include patterns every tool is
+// included; Exclude patterns always win.
+type ToolFilter struct {
+	Include []string
+	Exclude []string
+}
+
+// allows reports whether the tool with the given name passes the filter
+func (f ToolFilter) allows(name string) bool {
+	if len(f.Include) > 0 && !matchesAny(f.Include, name) {
+		return false
+	}
+	return !matchesAny(f.Exclude, name)
+}
+
+// matchesAny reports whether name matches one of the glob patterns
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAnyName reports whether the glob pattern matches one of names
+func matchesAnyName(pattern string, names []string) bool {
+	for _, name := range names {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks that every pattern is well formed and matches at least
+// one of the given tool names, so typos do not go unnoticed
+func (f ToolFilter) validate(names []string) error {
+	lists := []struct {
+		kind     string
+		patterns []string
+	}{
+		{"include", f.Include},
+		{"exclude", f.Exclude},
+	}
+
+	for _, list := range lists {
+		for _, pattern := range list.patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid %s pattern %q: %w",
+					list.kind, pattern, err)
+			}
+			if !matchesAnyName(pattern, names) {
+				return fmt.Errorf(
+					"%s pattern %q matches no tool, available tools: %s",
+					list.kind, pattern, strings.Join(names, ", "))
+			}
+		}
+	}
+
+	return nil
+}
+
+// SetToolFilter applies the filter to every toolset in the group. It must
+// be called after the toolsets are added and fails when a pattern does
+// not match any of their tools.
+func (tg *ToolsetGroup) SetToolFilter(filter ToolFilter) error {
+	if err := filter.validate(tg.allToolNames()); err != nil {
+		return err
+	}
+
+	for _, toolset := range tg.Toolsets {
+		toolset.filter = filter
+	}
+	return nil
+}
+
+// allToolNames returns the sorted names of the tools of every toolset,
+// before filtering
+func (tg *ToolsetGroup) allToolNames() []string {
+	var names []string
+	for _, toolset := range tg.Toolsets {
+		for _, tool := range toolset.allTools() {
+			names = append(names, tool.GetName())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// allTools returns the tools of the toolset before filtering
+func (t *Toolset) allTools() []mcpgo.Tool {
+	return append(append([]mcpgo.Tool{}, t.readTools...), t.writeTools...)
+}
@@ -0,0 +1,79 @@
+package toolsets
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/mcpgo"
+)
+
+func newFilterTestGroup() *ToolsetGroup {
+	tool := func(name string) mcpgo.Tool {
+		return mcpgo.NewTool(name, name, []mcpgo.ToolParameter{},
+			func(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.ToolResult, error) {
+				return mcpgo.NewToolResultText(name), nil
+			})
+	}
+
+	group := NewToolsetGroup(false)
+	group.AddToolset(NewToolset("link", "links").
+		AddReadTools(tool("get_all_links"), tool("get_link_by_id")).
+		AddWriteTools(tool("create_link"), tool("delete_link_by_id"), tool("delete_links")))
+	return group
+}
+
+func activeToolNames(t *Toolset) []string {
+	names := []string{}
+	for _, tool := range t.GetActiveTools() {
+		names = append(names, tool.GetName())
+	}
+	return names
+}
+
+func TestSetToolFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   ToolFilter
+		expected []string
+	}{
+		{
+			name: "no patterns keeps every tool",
+			expected: []string{
+				"get_all_links", "get_link_by_id",
+				"create_link", "delete_link_by_id", "delete_links",
+			},
+		},
+		{
+			name:     "include and exclude",
+			filter:   ToolFilter{Include: []string{"get_*", "create_link"}, Exclude: []string{"get_link_by_id"}},
+			expected: []string{"get_all_links", "create_link"},
+		},
+		{
+			name:     "exclude only",
+			filter:   ToolFilter{Exclude: []string{"delete_*"}},
+			expected: []string{"get_all_links", "get_link_by_id", "create_link"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			group := newFilterTestGroup()
+			require.NoError(t, group.SetToolFilter(tt.filter))
+			assert.Equal(t, tt.expected, activeToolNames(group.Toolsets["link"]))
+		})
+	}
+}
+
+func TestSetToolFilterRejectsUnmatchedPatterns(t *testing.T) {
+	group := newFilterTestGroup()
+
+	err := group.SetToolFilter(ToolFilter{Exclude: []string{"remove_*"}})
+	assert.EqualError(t, err, `exclude pattern "remove_*" matches no tool, available tools: `+
+		"create_link, delete_link_by_id, delete_links, get_all_links, get_link_by_id")
+
+	err = group.SetToolFilter(ToolFilter{Include: []string{"get_[a"}})
+	assert.ErrorContains(t, err, `invalid include pattern "get_[a"`)
+}
@@ -0,0 +1,85 @@
+package toolsets
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/mcpgo"
+)
+
+// IsReadOnly reports whether the group is in read-only mode
+func (tg *ToolsetGroup) IsReadOnly() bool {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+	return tg.readOnly
+}
+
+// Reconfigure changes the enabled toolsets, read-only mode and tool filter
+// while the server runs. When the registered tools change, they are
+// replaced on the server, which notifies clients with tools/list_changed.
+// It reports whether the tools changed. In dynamic mode, toolsets the
+// model enabled stay enabled.
+func (tg *ToolsetGroup) Reconfigure(enabledToolsets []string, readOnly bool, filter ToolFilter) (bool, error) {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+
+	for _, name := range enabledToolsets {
+		if _, exists := tg.Toolsets[name]; !exists {
+			return false, fmt.Errorf("toolset %s does not exist", name)
+		}
+	}
+
+	if err := filter.validate(tg.allToolNames()); err != nil {
+		return false, err
+	}
+
+	before := toolNames(tg.registeredTools())
+
+	enabled := make(map[string]bool, len(enabledToolsets))
+	for _, name := range enabledToolsets {
+		enabled[name] = true
+	}
+	everything := len(enabledToolsets) == 0 && !tg.dynamic
+
+	tg.readOnly = readOnly
+	tg.everythingOn = everything
+	for name, toolset := range tg.Toolsets {
+		toolset.readOnly = readOnly
+		toolset.filter = filter
+		toolset.Enabled = everything || enabled[name] || toolset.alwaysEnabled ||
+			(tg.dynamic && toolset.Enabled)
+	}
+
+	tools := tg.registeredTools()
+	if tg.server == nil || slices.Equal(before, toolNames(tools)) {
+		return false, nil
+	}
+
+	tg.server.SetTools(tools...)
+	return true, nil
+}
+
+// registeredTools returns the tools the group registers with the server
+func (tg *ToolsetGroup) registeredTools() []mcpgo.Tool {
+	var tools []mcpgo.Tool
+	if tg.dynamic {
+		tools = append(tools, tg.dynamicTools()...)
+	}
+	for _, toolset := range tg.Toolsets {
+		if toolset.Enabled {
+			tools = append(tools, toolset.GetActiveTools()...)
+		}
+	}
+	return tools
+}
+
+// toolNames returns the sorted names of the tools
+func toolNames(tools []mcpgo.Tool) []string {
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		names = append(names, tool.GetName())
+	}
+	sort.Strings(names)
+	return names
+}
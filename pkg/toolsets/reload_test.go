@@ -0,0 +1,62 @@
+package toolsets
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/irfansofyana/linkwarden-mcp-server/pkg/mcpgo"
+)
+
+// recordingServer records the tools registered with it
+type recordingServer struct {
+	tools    []string
+	replaced int
+}
+
+func (s *recordingServer) AddTools(tools ...mcpgo.Tool) {
+	s.tools = append(s.tools, toolNames(tools)...)
+}
+
+func (s *recordingServer) SetTools(tools ...mcpgo.Tool) {
+	s.tools = toolNames(tools)
+	s.replaced++
+}
+
+func (s *recordingServer) AddPrompts(prompts ...mcpgo.Prompt) {}
+
+func TestReconfigureReplacesChangedTools(t *testing.T) {
+	group := newFilterTestGroup()
+	group.AddToolset(NewToolset("tags", "tags").
+		AddReadTools(mcpgo.NewTool("get_all_tags", "", []mcpgo.ToolParameter{},
+			func(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.ToolResult, error) {
+				return mcpgo.NewToolResultText("tags"), nil
+			})))
+	require.NoError(t, group.EnableToolsets([]string{"link"}))
+
+	server := &recordingServer{}
+	group.RegisterTools(server)
+
+	changed, err := group.Reconfigure([]string{"link"}, true, ToolFilter{})
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, []string{"get_all_links", "get_link_by_id"}, server.tools)
+	assert.True(t, group.IsReadOnly())
+
+	changed, err = group.Reconfigure([]string{"link"}, true, ToolFilter{})
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	changed, err = group.Reconfigure(nil, false, ToolFilter{Exclude: []string{"delete_*"}})
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, []string{"create_link", "get_all_links", "get_all_tags", "get_link_by_id"}, server.tools)
+	assert.Equal(t, 2, server.replaced)
+
+	// An invalid config leaves the tools as they are
+	_, err = group.Reconfigure([]string{"bookmarks"}, false, ToolFilter{})
+	assert.EqualError(t, err, "toolset bookmarks does not exist")
+	assert.Equal(t, 2, server.replaced)
+}
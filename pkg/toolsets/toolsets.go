@@ -2,18 +2,24 @@ package toolsets
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/irfansofyana/linkwarden-mcp-server/pkg/mcpgo"
 )
 
 // Toolset represents a group of related tools
 type Toolset struct {
-	Name        string
-	Description string
-	Enabled     bool
-	readOnly    bool
-	writeTools  []mcpgo.Tool
-	readTools   []mcpgo.Tool
+	Name          string
+	Description   string
+	Enabled       bool
+	alwaysEnabled bool
+	readOnly      bool
+	filter        ToolFilter
+	customization ToolCustomization
+	writeTools    []mcpgo.Tool
+	readTools     []mcpgo.Tool
 }
 
 // ToolsetGroup manages multiple toolsets
@@ -21,6 +27,54 @@ type ToolsetGroup struct {
 	Toolsets     map[string]*Toolset
 	everythingOn bool
 	readOnly     bool
+	dynamic      bool
+
+	customization ToolCustomization
+
+	// mu guards enabling toolsets once the server is running
+	mu     sync.Mutex
+	server mcpgo.Server
+}
+
+// readToolAnnotations are the default hints for tools that only read data
+var readToolAnnotations = mcpgo.ToolAnnotations{
+	ReadOnlyHint:    boolPtr(true),
+	DestructiveHint: boolPtr(false),
+	IdempotentHint:  boolPtr(true),
+	OpenWorldHint:   boolPtr(false),
+}
+
+// writeToolAnnotations are the default hints for tools that modify data.
+// Tools that delete data override DestructiveHint themselves.
+var writeToolAnnotations = mcpgo.ToolAnnotations{
+	ReadOnlyHint:    boolPtr(false),
+	DestructiveHint: boolPtr(false),
+	IdempotentHint:  boolPtr(false),
+	OpenWorldHint:   boolPtr(false),
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+// applyDefaultAnnotations fills in the tool hints and a title derived
+// from the tool name, keeping anything the tool set itself
+func applyDefaultAnnotations(
+	tools []mcpgo.Tool, defaults mcpgo.ToolAnnotations) {
+	for _, tool := range tools {
+		toolDefaults := defaults
+		toolDefaults.Title = titleFromName(tool.GetName())
+		tool.SetDefaultAnnotations(toolDefaults)
+	}
+}
+
+// titleFromName turns a tool name like get_all_links into "Get all links"
+func titleFromName(name string) string {
+	title := strings.ReplaceAll(name, "_", " ")
+	if title == "" {
+		return title
+	}
+	return strings.ToUpper(title[:1]) + title[1:]
 }
 
 // NewToolset creates a new toolset with the given name and description
@@ -42,33 +96,69 @@ func NewToolsetGroup(readOnly bool) *ToolsetGroup {
 	}
 }
 
-// AddWriteTools adds write tools to the toolset
+// EnableDynamicMode makes the group expose only the tools to discover and
+// enable toolsets, so other toolsets are registered once they are needed
+func (tg *ToolsetGroup) EnableDynamicMode() {
+	tg.dynamic = true
+}
+
+// AddWriteTools adds write tools to the toolset. They are kept in
+// read-only mode too, in case the mode is turned off while running.
 func (t *Toolset) AddWriteTools(tools ...mcpgo.Tool) *Toolset {
-	if !t.readOnly {
-		t.writeTools = append(t.writeTools, tools...)
-	}
+	applyDefaultAnnotations(tools, writeToolAnnotations)
+	t.writeTools = append(t.writeTools, tools...)
+	return t
+}
+
+// SetAlwaysEnabled makes the toolset enabled whichever toolsets are
+// selected, for tools the others depend on
+func (t *Toolset) SetAlwaysEnabled() *Toolset {
+	t.alwaysEnabled = true
 	return t
 }
 
 // AddReadTools adds read tools to the toolset
 func (t *Toolset) AddReadTools(tools ...mcpgo.Tool) *Toolset {
+	applyDefaultAnnotations(tools, readToolAnnotations)
 	t.readTools = append(t.readTools, tools...)
 	return t
 }
 
-// RegisterTools registers all active tools with the server
-func (t *Toolset) RegisterTools(s mcpgo.Server) {
-	if !t.Enabled {
-		return
+// CustomizeTools applies the options to every tool added to the toolset
+func (t *Toolset) CustomizeTools(opts ...mcpgo.ToolOption) *Toolset {
+	for i, tool := range t.readTools {
+		t.readTools[i] = tool.Customize(opts...)
 	}
-	for _, tool := range t.readTools {
-		s.AddTools(tool)
+	for i, tool := range t.writeTools {
+		t.writeTools[i] = tool.Customize(opts...)
 	}
+	return t
+}
+
+// GetActiveTools returns the tools the toolset registers when enabled,
+// under the names and descriptions clients see
+func (t *Toolset) GetActiveTools() []mcpgo.Tool {
+	candidates := append([]mcpgo.Tool{}, t.readTools...)
 	if !t.readOnly {
-		for _, tool := range t.writeTools {
-			s.AddTools(tool)
+		candidates = append(candidates, t.writeTools...)
+	}
+
+	tools := make([]mcpgo.Tool, 0, len(candidates))
+	for _, tool := range candidates {
+		if t.filter.allows(tool.GetName()) {
+			tools = append(tools, t.customization.apply(tool))
 		}
 	}
+	return tools
+}
+
+// RegisterTools registers all active tools with the server
+func (t *Toolset) RegisterTools(s mcpgo.Server) {
+	if !t.Enabled {
+		return
+	}
+	// Register all tools at once so clients are notified a single time
+	s.AddTools(t.GetActiveTools()...)
 }
 
 // AddToolset adds a toolset to the group
@@ -79,6 +169,16 @@ func (tg *ToolsetGroup) AddToolset(ts *Toolset) {
 	tg.Toolsets[ts.Name] = ts
 }
 
+// Names returns the sorted names of the toolsets in the group
+func (tg *ToolsetGroup) Names() []string {
+	names := make([]string, 0, len(tg.Toolsets))
+	for name := range tg.Toolsets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // EnableToolset enables a specific toolset
 func (tg *ToolsetGroup) EnableToolset(name string) error {
 	toolset, exists := tg.Toolsets[name]
@@ -89,12 +189,19 @@ func (tg *ToolsetGroup) EnableToolset(name string) error {
 	return nil
 }
 
-// EnableToolsets enables multiple toolsets
+// EnableToolsets enables multiple toolsets. Without names every toolset is
+// enabled, except in dynamic mode where toolsets are enabled on request.
 func (tg *ToolsetGroup) EnableToolsets(names []string) error {
-	if len(names) == 0 {
+	if len(names) == 0 && !tg.dynamic {
 		tg.everythingOn = true
 	}
 
+	for _, toolset := range tg.Toolsets {
+		if toolset.alwaysEnabled {
+			toolset.Enabled = true
+		}
+	}
+
 	for _, name := range names {
 		err := tg.EnableToolset(name)
 		if err != nil {
@@ -115,8 +222,17 @@ func (tg *ToolsetGroup) EnableToolsets(names []string) error {
 	return nil
 }
 
-// RegisterTools registers all active toolsets with the server
+// RegisterTools registers all active toolsets with the server. In dynamic
+// mode it also registers the tools to discover and enable toolsets.
 func (tg *ToolsetGroup) RegisterTools(s mcpgo.Server) {
+	tg.mu.Lock()
+	defer tg.mu.Unlock()
+
+	tg.server = s
+	if tg.dynamic {
+		s.AddTools(tg.dynamicTools()...)
+	}
+
 	for _, toolset := range tg.Toolsets {
 		toolset.RegisterTools(s)
 	}